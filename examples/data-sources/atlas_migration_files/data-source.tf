@@ -0,0 +1,7 @@
+data "atlas_migration_files" "app" {
+  dir = "migrations?format=atlas"
+}
+
+output "migration_count" {
+  value = length(data.atlas_migration_files.app.files)
+}
@@ -0,0 +1,5 @@
+data "atlas_version" "current" {}
+
+output "atlas_version" {
+  value = data.atlas_version.current.version
+}
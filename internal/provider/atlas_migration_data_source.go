@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"path"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -37,6 +38,8 @@ type (
 		Next    types.String `tfsdk:"next"`
 		Latest  types.String `tfsdk:"latest"`
 		ID      types.String `tfsdk:"id"`
+
+		PendingVersions types.List `tfsdk:"pending_versions"`
 	}
 	RemoteDirBlock struct {
 		Name types.String `tfsdk:"name"`
@@ -110,9 +113,10 @@ func (d *MigrationDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 		},
 		Attributes: map[string]schema.Attribute{
 			"config": schema.StringAttribute{
-				Description: "The configuration file for the migration",
-				Optional:    true,
-				Sensitive:   false,
+				Description: "The configuration file for the migration. If unset, " +
+					"the file set by the ATLAS_CONFIG environment variable is used",
+				Optional:  true,
+				Sensitive: false,
 			},
 			"variables": schema.StringAttribute{
 				Description: "Stringify JSON object containing variables to be used inside the Atlas configuration file.",
@@ -155,6 +159,11 @@ func (d *MigrationDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 				Description: "The ID of the migration",
 				Computed:    true,
 			},
+			"pending_versions": schema.ListAttribute{
+				Description: "The versions of the pending migrations, in the order they are applied",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -204,6 +213,11 @@ func (d *MigrationDataSource) Read(ctx context.Context, req datasource.ReadReque
 	} else {
 		data.Next = types.StringValue(r.Next)
 	}
+	pending := make([]attr.Value, 0, len(r.Pending))
+	for _, f := range r.Pending {
+		pending = append(pending, types.StringValue(f.Version))
+	}
+	data.PendingVersions = types.ListValueMust(types.StringType, pending)
 	v := r.LatestVersion()
 	if data.RemoteDir != nil {
 		u, err := data.RemoteDir.AtlasURL()
@@ -253,6 +267,12 @@ func (d *MigrationDataSourceModel) Workspace(_ context.Context, p *ProviderData)
 			},
 		},
 	}
+	if cfg.Config == "" {
+		// Fall back to the atlas.hcl used by the Atlas CLI.
+		if cfg.Config, err = defaultConfig(cfg.EnvName); err != nil {
+			return nil, nil, err
+		}
+	}
 	if rd := d.RemoteDir; rd != nil {
 		cfg.Env.Migration.DirURL, err = rd.AtlasURL()
 	} else {
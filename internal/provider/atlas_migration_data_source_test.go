@@ -37,6 +37,13 @@ func TestAccMigrationDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.atlas_migration.hello", "current", ""),
 					resource.TestCheckResourceAttr("data.atlas_migration.hello", "next", "20221101163823"),
 					resource.TestCheckResourceAttr("data.atlas_migration.hello", "latest", "20221101165415"),
+					resource.TestCheckResourceAttr("data.atlas_migration.hello", "pending_versions.#", "6"),
+					resource.TestCheckResourceAttr("data.atlas_migration.hello", "pending_versions.0", "20221101163823"),
+					resource.TestCheckResourceAttr("data.atlas_migration.hello", "pending_versions.1", "20221101163841"),
+					resource.TestCheckResourceAttr("data.atlas_migration.hello", "pending_versions.2", "20221101164227"),
+					resource.TestCheckResourceAttr("data.atlas_migration.hello", "pending_versions.3", "20221101165036"),
+					resource.TestCheckResourceAttr("data.atlas_migration.hello", "pending_versions.4", "20221101165147"),
+					resource.TestCheckResourceAttr("data.atlas_migration.hello", "pending_versions.5", "20221101165415"),
 				),
 			},
 		},
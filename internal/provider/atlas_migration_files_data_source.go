@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"ariga.io/atlas/sql/migrate"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type (
+	// MigrationFilesDataSource defines the data source implementation.
+	MigrationFilesDataSource struct {
+		ProviderData
+	}
+	// MigrationFilesDataSourceModel describes the data source data model.
+	MigrationFilesDataSourceModel struct {
+		DirURL types.String `tfsdk:"dir"`
+		Files  types.List   `tfsdk:"files"`
+		ID     types.String `tfsdk:"id"`
+	}
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource                   = &MigrationFilesDataSource{}
+	_ datasource.DataSourceWithConfigure      = &MigrationFilesDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &MigrationFilesDataSource{}
+)
+
+var (
+	migrationFileAttrs = map[string]attr.Type{
+		"version":     types.StringType,
+		"name":        types.StringType,
+		"description": types.StringType,
+		"checksum":    types.StringType,
+	}
+)
+
+// NewMigrationFilesDataSource returns a new MigrationFilesDataSource.
+func NewMigrationFilesDataSource() datasource.DataSource {
+	return &MigrationFilesDataSource{}
+}
+
+// Metadata implements datasource.DataSource.
+func (d *MigrationFilesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_migration_files"
+}
+
+// Configure implements datasource.DataSourceWithConfigure.
+func (d *MigrationFilesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	resp.Diagnostics.Append(d.configure(req.ProviderData)...)
+}
+
+// Schema implements datasource.DataSource.
+func (d *MigrationFilesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data source lists the files of a local migration directory, without connecting to a database.",
+		Attributes: map[string]schema.Attribute{
+			"dir": schema.StringAttribute{
+				Description: "The URL of the local migration directory. Default: migrations",
+				Optional:    true,
+			},
+			"files": schema.ListNestedAttribute{
+				Description: "The migration files, in the order they are applied",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"version": schema.StringAttribute{
+							Description: "The version of the migration file",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "The name of the migration file",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "The description part of the file name",
+							Computed:    true,
+						},
+						"checksum": schema.StringAttribute{
+							Description: "The checksum of the file, as recorded in the atlas.sum file",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				Description: "The ID of the migration directory",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// ValidateConfig implements datasource.DataSourceWithValidateConfig.
+func (d *MigrationFilesDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data MigrationFilesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.DirURL.IsUnknown() {
+		return
+	}
+	if u, err := url.Parse(filepath.ToSlash(data.DirURL.ValueString())); err == nil && u.Scheme == SchemaTypeAtlas {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("dir"),
+			"Remote directory",
+			"Only local migration directories are supported",
+		)
+	}
+}
+
+// Read implements datasource.DataSource.
+func (d *MigrationFilesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data MigrationFilesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	dirURL, err := absoluteFileURL(defaultString(data.DirURL, "migrations"))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse migration directory URL", err.Error())
+		return
+	}
+	files, err := migrationFiles(dirURL)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read migration directory", err.Error())
+		return
+	}
+	data.Files = types.ListValueMust(types.ObjectType{AttrTypes: migrationFileAttrs}, files)
+	data.ID = dirToID(types.StringValue(dirURL))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// migrationFiles returns the files of the local migration directory
+// at the given URL. The directory is validated against its atlas.sum.
+func migrationFiles(dirURL string) ([]attr.Value, error) {
+	u, err := url.Parse(dirURL)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := migrate.NewLocalDir(filepath.Join(u.Host, u.Path))
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate.Validate(dir); err != nil {
+		return nil, err
+	}
+	files, err := dir.Files()
+	if err != nil {
+		return nil, err
+	}
+	sums, err := dir.Checksum()
+	if err != nil {
+		return nil, err
+	}
+	vs := make([]attr.Value, 0, len(files))
+	for _, f := range files {
+		sum, err := sums.SumByName(f.Name())
+		if err != nil {
+			return nil, fmt.Errorf("checksum of %q: %w", f.Name(), err)
+		}
+		vs = append(vs, types.ObjectValueMust(migrationFileAttrs, map[string]attr.Value{
+			"version":     types.StringValue(f.Version()),
+			"name":        types.StringValue(f.Name()),
+			"description": types.StringValue(f.Desc()),
+			"checksum":    types.StringValue(sum),
+		}))
+	}
+	return vs, nil
+}
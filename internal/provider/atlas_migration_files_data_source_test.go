@@ -0,0 +1,36 @@
+package provider_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccMigrationFilesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "atlas_migration_files" "hello" {
+					dir = "migrations?format=atlas"
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.atlas_migration_files.hello", "files.#", "6"),
+					resource.TestCheckResourceAttr("data.atlas_migration_files.hello", "files.0.version", "20221101163823"),
+					resource.TestCheckResourceAttr("data.atlas_migration_files.hello", "files.0.name", "20221101163823_create_users.sql"),
+					resource.TestCheckResourceAttr("data.atlas_migration_files.hello", "files.0.description", "create_users"),
+					resource.TestCheckResourceAttrSet("data.atlas_migration_files.hello", "files.0.checksum"),
+					resource.TestCheckResourceAttr("data.atlas_migration_files.hello", "files.5.version", "20221101165415"),
+				),
+			},
+			{
+				Config: `data "atlas_migration_files" "hello" {
+					dir = "atlas://test"
+				}`,
+				ExpectError: regexp.MustCompile("Only local migration directories are supported"),
+			},
+		},
+	})
+}
@@ -0,0 +1,21 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_isKnownList(t *testing.T) {
+	require.True(t, isKnownList(types.ListNull(types.StringType)))
+	require.False(t, isKnownList(types.ListUnknown(types.StringType)))
+	require.True(t, isKnownList(types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("mysql://localhost:3306/app"),
+	})))
+	require.False(t, isKnownList(types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("mysql://localhost:3306/app"),
+		types.StringUnknown(),
+	})))
+}
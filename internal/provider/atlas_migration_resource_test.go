@@ -2,14 +2,17 @@ package provider_test
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	atlas "ariga.io/atlas-go-sdk/atlasexec"
 	"ariga.io/atlas/sql/migrate"
@@ -58,6 +61,10 @@ func TestAccMigrationResource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101163841"),
 					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.next", "20221101164227"),
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "applied_files.#", "1"),
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "applied_files.0", "20221101163841_create_pets.sql"),
+					// No dev database is configured, so the migrations are not linted.
+					resource.TestCheckNoResourceAttr("atlas_migration.testdb", "lint_report"),
 				),
 			},
 		},
@@ -309,6 +316,123 @@ HCL
 	})
 }
 
+func TestAccMigrationResource_ConfigFile(t *testing.T) {
+	var (
+		schema1 = "test_configfile"
+	)
+	tempSchemas(t, mysqlURL, schema1)
+
+	cfgFile := filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(cfgFile, []byte(`
+variable "url" {
+	type = string
+}
+env "tf" {
+	url = var.url
+	migration {
+		dir = "this-dir-does-not-exist-and-always-gets-overrides"
+	}
+}
+`), 0644))
+	config := fmt.Sprintf(`
+	resource "atlas_migration" "testdb" {
+		dir         = "file://migrations"
+		version     = "20221101163841"
+		env_name    = "tf"
+		config_file = %[1]q
+		variables   = jsonencode({
+			url = "%[2]s/%[3]s"
+		})
+	}`, cfgFile, mysqlURL, schema1)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101163841"),
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.next", "20221101164227"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "testdb" {
+					dir         = "file://migrations"
+					env_name    = "tf"
+					config      = ""
+					config_file = %[1]q
+				}`, cfgFile),
+				ExpectError: regexp.MustCompile("Only one of config or config_file can be set"),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_URLs(t *testing.T) {
+	var (
+		schema1 = "test_urls_1"
+		schema2 = "test_urls_2"
+	)
+	c := tempSchemas(t, mysqlURL, schema1, schema2)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "testdb" {
+					dir     = "migrations?format=atlas"
+					version = "20221101163841"
+					urls    = ["%[1]s/%[2]s", "%[1]s/%[3]s"]
+				}`, mysqlURL, schema1, schema2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101163841"),
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.next", "20221101164227"),
+					resource.TestCheckNoResourceAttr("atlas_migration.testdb", "atlas_hcl_rendered"),
+					func(s *terraform.State) error {
+						// The migrations are applied to both databases.
+						for _, name := range []string{schema1, schema2} {
+							var n int
+							err := c.DB.QueryRowContext(context.Background(),
+								fmt.Sprintf("SELECT COUNT(*) FROM `%s`.atlas_schema_revisions", name)).Scan(&n)
+							require.NoError(t, err)
+							require.NotZero(t, n)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "testdb" {
+					dir  = "migrations?format=atlas"
+					url  = "%[1]s/%[2]s"
+					urls = ["%[1]s/%[3]s"]
+				}`, mysqlURL, schema1, schema2),
+				ExpectError: regexp.MustCompile("Only one of url or urls can be set"),
+			},
+			{
+				Config: fmt.Sprintf(`
+				provider "atlas" {
+					debug = true
+				}
+				resource "atlas_migration" "testdb" {
+					dir     = "migrations?format=atlas"
+					version = "20221101164227"
+					urls    = ["%[1]s/%[2]s", "%[1]s/%[3]s"]
+				}`, mysqlURL, schema1, schema2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101164227"),
+					resource.TestMatchResourceAttr("atlas_migration.testdb", "atlas_hcl_rendered",
+						regexp.MustCompile(`(?s)env "tf" \{.+migration \{`)),
+				),
+			},
+		},
+	})
+}
+
 func TestAccMigrationResource_WithLatestVersion(t *testing.T) {
 	schema := "test_1"
 	tempSchemas(t, mysqlURL, schema)
@@ -449,6 +573,473 @@ func TestAccMigrationResource_WithLatestVersion(t *testing.T) {
 	})
 }
 
+func TestAccMigrationResource_PostMigrateVerify(t *testing.T) {
+	schema := "test_verify"
+	tempSchemas(t, mysqlURL, schema)
+	tempSchemas(t, mysqlDevURL, schema)
+	config := `
+	resource "atlas_migration" "testdb" {
+		dir                 = "migrations?format=atlas"
+		version             = "%[2]s"
+		url                 = "%[1]s"
+		post_migrate_verify = "%[3]s"
+	}`
+	schemaURL := fmt.Sprintf("%s/%s", mysqlURL, schema)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, schemaURL, "20221101164227",
+					"SELECT COUNT(*) FROM users"),
+				ExpectError: regexp.MustCompile("The verification query returned a falsy value"),
+			},
+			{
+				Config: fmt.Sprintf(config, schemaURL, "20221101165415",
+					"SELECT COUNT(*) FROM users"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101165415"),
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "post_migrate_verify", "SELECT COUNT(*) FROM users"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, schemaURL, "20221101165415",
+					"SELECT id FROM users WHERE name = 'unknown'"),
+				ExpectError: regexp.MustCompile("The verification query returned no rows"),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_PostMigrateVerifySQLite(t *testing.T) {
+	var (
+		dir = localMigrationDir(t,
+			"1_create_users.sql", "CREATE TABLE users (id int, name text);",
+			"2_insert_users.sql", "INSERT INTO users (id, name) VALUES (1, 'a8m');",
+		)
+		dbURL  = fmt.Sprintf("sqlite://%s?_fk=true", filepath.Join(t.TempDir(), "sqlite.db"))
+		config = `
+	resource "atlas_migration" "testdb" {
+		dir                 = "%[1]s"
+		version             = "%[3]s"
+		url                 = "%[2]s"
+		post_migrate_verify = "SELECT COUNT(*) FROM users"
+	}`
+	)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(config, dir, dbURL, "1"),
+				ExpectError: regexp.MustCompile("The verification query returned a falsy value"),
+			},
+			{
+				Config: fmt.Sprintf(config, dir, dbURL, "2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_MigrationHooks(t *testing.T) {
+	schema := "test_hooks"
+	c := tempSchemas(t, mysqlURL, schema, "test_hooks_log")
+	config := fmt.Sprintf(`
+	resource "atlas_migration" "testdb" {
+		dir     = "migrations?format=atlas"
+		version = "20221101165415"
+		url     = "%s/%s"
+		migration_hooks {
+			before = "CREATE TABLE test_hooks_log.log (id int);"
+			after  = "INSERT INTO test_hooks_log.log (id) SELECT COUNT(*) FROM users;"
+		}
+	}`, mysqlURL, schema)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "testdb" {
+					dir = "migrations?format=atlas"
+					url = "%s/%s"
+					migration_hooks {
+						before = "SELECT 1"
+					}
+				}`, mysqlURL, schema),
+				ExpectError: regexp.MustCompile("Invalid migration hook"),
+			},
+			{
+				Config: `
+				resource "atlas_migration" "testdb" {
+					dir = "migrations?format=atlas"
+					url = "docker://mysql/8/test_hooks"
+					migration_hooks {
+						before = "CREATE TABLE test_hooks_log.log (id int);"
+					}
+				}`,
+				ExpectError: regexp.MustCompile(`the "docker" scheme is not supported`),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101165415"),
+					func(s *terraform.State) error {
+						var n int
+						err := c.DB.QueryRowContext(context.Background(), "SELECT id FROM test_hooks_log.log").Scan(&n)
+						require.NoError(t, err)
+						require.Equal(t, 1, n)
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_MigrationHooksSQLite(t *testing.T) {
+	var (
+		dir = localMigrationDir(t,
+			"1_create_users.sql", "CREATE TABLE users (id int); CREATE TABLE hooks_log (id int);",
+			"2_insert_users.sql", "INSERT INTO users (id) VALUES (1), (2);",
+		)
+		dbPath = filepath.Join(t.TempDir(), "sqlite.db")
+		dbURL  = fmt.Sprintf("sqlite://%s?_fk=true", dbPath)
+	)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "testdb" {
+					dir = "%s"
+					url = "%s"
+					migration_hooks {
+						before = "PRAGMA user_version = 7;"
+						after  = "INSERT INTO hooks_log (id) SELECT COUNT(*) FROM users;"
+					}
+				}`, dir, dbURL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "2"),
+					func(s *terraform.State) error {
+						db, err := sql.Open("sqlite", dbPath)
+						require.NoError(t, err)
+						defer db.Close()
+						var v, n int
+						require.NoError(t, db.QueryRowContext(context.Background(), "PRAGMA user_version").Scan(&v))
+						require.Equal(t, 7, v)
+						require.NoError(t, db.QueryRowContext(context.Background(), "SELECT id FROM hooks_log").Scan(&n))
+						require.Equal(t, 2, n)
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_DeploymentPolicy(t *testing.T) {
+	schema := "test_policy"
+	tempSchemas(t, mysqlURL, schema)
+	config := `
+	resource "atlas_migration" "testdb" {
+		dir                     = "migrations?format=atlas"
+		version                 = "%[2]s"
+		url                     = "%[1]s"
+		cloud_deployment_policy = "%[3]s"
+	}`
+	schemaURL := fmt.Sprintf("%s/%s", mysqlURL, schema)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, schemaURL, "20221101163823", "manual"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("atlas_migration.testdb", "dir_hash"),
+					resource.TestCheckNoResourceAttr("atlas_migration.testdb", "status.current"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, schemaURL, "20221101163823", "on_change"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("atlas_migration.testdb", "status.current"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, schemaURL, "20221101163823", "always"),
+				// The always policy forces an update on every run.
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101163823"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_BaselineSQL(t *testing.T) {
+	schema := "test_baseline_sql"
+	tempSchemas(t, mysqlURL, schema)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "testdb" {
+					dir      = "migrations?format=atlas"
+					version  = "20221101165415"
+					baseline = "20221101165415"
+					url      = "%s/%s"
+				}`, mysqlURL, schema),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101165415"),
+					resource.TestCheckResourceAttrSet("atlas_migration.testdb", "schema_version"),
+					resource.TestMatchResourceAttr("atlas_migration.testdb", "baseline_migration_sql",
+						regexp.MustCompile("(?s)^-- 20221101163823_create_users.sql\n.+-- 20221101165415_insert_pets.sql\n")),
+				),
+			},
+			{
+				// Removing the baseline from the config unsets it.
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "testdb" {
+					dir     = "migrations?format=atlas"
+					version = "20221101165415"
+					url     = "%s/%s"
+				}`, mysqlURL, schema),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101165415"),
+					resource.TestCheckNoResourceAttr("atlas_migration.testdb", "baseline"),
+					resource.TestCheckNoResourceAttr("atlas_migration.testdb", "baseline_migration_sql"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_AutoBaseline(t *testing.T) {
+	schema := "test_auto_baseline"
+	c := tempSchemas(t, mysqlURL, schema)
+	tempSchemas(t, mysqlDevURL, schema)
+	// The database matches the first migration file.
+	createTables(t, c, fmt.Sprintf("CREATE TABLE `%s`.`users` ("+
+		"`id` bigint(20) NOT NULL AUTO_INCREMENT, `age` bigint(20) NOT NULL, "+
+		"`name` varchar(255) COLLATE utf8mb4_bin NOT NULL, "+
+		"PRIMARY KEY (`id`), UNIQUE KEY `age` (`age`))", schema))
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "testdb" {
+					dir           = "migrations?format=atlas"
+					version       = "20221101163841"
+					auto_baseline = true
+					url           = "%[1]s/%[3]s"
+					dev_url       = "%[2]s/%[3]s"
+				}`, mysqlURL, mysqlDevURL, schema),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "baseline", "20221101163823"),
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101163841"),
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "applied_files.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_ErrorOnPending(t *testing.T) {
+	schema := "test_error_on_pending"
+	tempSchemas(t, mysqlURL, schema)
+	config := func(version string) string {
+		return fmt.Sprintf(`
+		resource "atlas_migration" "testdb" {
+			dir              = "migrations?format=atlas"
+			version          = "%s"
+			error_on_pending = true
+			url              = "%s/%s"
+		}`, version, mysqlURL, schema)
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("20221101163841"),
+				ExpectError: regexp.MustCompile("The database has 2 pending migrations: 20221101163823, 20221101163841"),
+			},
+		},
+	})
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "testdb" {
+					dir     = "migrations?format=atlas"
+					version = "20221101163841"
+					url     = "%s/%s"
+				}`, mysqlURL, schema),
+			},
+			{
+				// The database is fully migrated to the requested version.
+				Config: config("20221101163841"),
+			},
+			{
+				Config:      config("20221101164227"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("The database has 1 pending migrations: 20221101164227"),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_Debug(t *testing.T) {
+	schema := "test_debug"
+	tempSchemas(t, mysqlURL, schema)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				provider "atlas" {
+					debug = true
+				}
+				resource "atlas_migration" "testdb" {
+					dir     = "migrations?format=atlas"
+					version = "20221101163823"
+					url     = "%s/%s"
+				}`, mysqlURL, schema),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101163823"),
+					resource.TestMatchResourceAttr("atlas_migration.testdb", "atlas_hcl_rendered",
+						regexp.MustCompile(`(?s)env "tf" \{.+migration \{`)),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "testdb" {
+					dir     = "migrations?format=atlas"
+					version = "20221101163823"
+					url     = "%s/%s"
+				}`, mysqlURL, schema),
+				Check: resource.TestCheckNoResourceAttr("atlas_migration.testdb", "atlas_hcl_rendered"),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_ProviderLint(t *testing.T) {
+	schema := "test_provider_lint"
+	tempSchemas(t, mysqlURL, schema)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				provider "atlas" {
+					debug = true
+					lint {
+						review = "ERROR"
+					}
+				}
+				resource "atlas_migration" "testdb" {
+					dir     = "migrations?format=atlas"
+					version = "20221101163823"
+					url     = "%s/%s"
+				}`, mysqlURL, schema),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.testdb", "status.current", "20221101163823"),
+					resource.TestMatchResourceAttr("atlas_migration.testdb", "atlas_hcl_rendered",
+						regexp.MustCompile(`(?s)lint \{\s+review = "ERROR"`)),
+				),
+			},
+			{
+				// The resource policy takes precedence.
+				Config: fmt.Sprintf(`
+				provider "atlas" {
+					debug = true
+					lint {
+						review = "ERROR"
+					}
+				}
+				resource "atlas_migration" "testdb" {
+					dir     = "migrations?format=atlas"
+					version = "20221101163823"
+					url     = "%s/%s"
+					lint {
+						review = "ALWAYS"
+					}
+				}`, mysqlURL, schema),
+				Check: resource.TestMatchResourceAttr("atlas_migration.testdb", "atlas_hcl_rendered",
+					regexp.MustCompile(`(?s)lint \{\s+review = "ALWAYS"`)),
+			},
+		},
+	})
+}
+
+func TestAccMigrationResource_Import(t *testing.T) {
+	schema := "test_import_migration"
+	tempSchemas(t, mysqlURL, schema)
+	dbURL := fmt.Sprintf("%s/%s", mysqlURL, schema)
+	config := fmt.Sprintf(`
+	resource "atlas_migration" "testdb" {
+		dir     = "migrations?format=atlas"
+		version = "20221101163841"
+		url     = "%s"
+	}`, dbURL)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					// Migrate the database outside of Terraform.
+					c, err := atlas.NewClient("", "atlas")
+					require.NoError(t, err)
+					_, err = c.MigrateApply(context.Background(), &atlas.MigrateApplyParams{
+						URL:    dbURL,
+						DirURL: "file://migrations",
+						Amount: 2,
+					})
+					require.NoError(t, err)
+				},
+				Config:             config,
+				ResourceName:       "atlas_migration.testdb",
+				ImportState:        true,
+				ImportStateId:      "migrations?format=atlas|" + dbURL,
+				ImportStatePersist: true,
+				ImportStateCheck: func(s []*terraform.InstanceState) error {
+					if len(s) != 1 {
+						return fmt.Errorf("expected 1 imported resource, got %d", len(s))
+					}
+					if v := s[0].Attributes["version"]; v != "20221101163841" {
+						return fmt.Errorf("unexpected imported version: %q", v)
+					}
+					if v := s[0].Attributes["status.current"]; v != "20221101163841" {
+						return fmt.Errorf("unexpected imported status.current: %q", v)
+					}
+					return nil
+				},
+			},
+			{
+				// The imported state matches the configuration.
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 func TestAccMigrationResource_NoLongerExists(t *testing.T) {
 	schema := "test_1"
 	c := tempSchemas(t, mysqlURL, schema)
@@ -808,6 +1399,7 @@ func TestAccMigrationResource_RequireApproval(t *testing.T) {
 	)
 	var (
 		flow   []*DeploymentApprovalsStatus
+		polls  []time.Time
 		byTag  = make(map[string]migrate.Dir)
 		devURL = "sqlite://file::memory:?cache=shared"
 		dbURL  = fmt.Sprintf("sqlite://%s?_fk=true", filepath.Join(t.TempDir(), "sqlite.db"))
@@ -842,6 +1434,7 @@ func TestAccMigrationResource_RequireApproval(t *testing.T) {
 					fmt.Fprintf(w, `{"data":{"dir":{"protectedFlows":{"migrateDown": true}}}}`)
 				case strings.Contains(m.Query, "migratePlanByExtID"):
 					require.NotEmpty(t, flow)
+					polls = append(polls, time.Now())
 					status := flow[0]
 					flow = flow[1:]
 					if status != nil {
@@ -1062,6 +1655,70 @@ HCL
 			},
 		},
 	})
+	// plan is waiting for approval, and checked again after the polling_interval
+	flow, polls = append(flow, newS(PlanPendingApproval), newS(PlanApproved)), nil
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				provider "atlas" {
+					dev_url = "%[1]s"
+				}
+				resource "atlas_migration" "hello" {
+					url      = "%[3]s"
+					dir      = "atlas://test?tag=tag2"
+					env_name = "tf"
+					config   = <<-HCL
+atlas {
+  cloud {
+    token = "aci_bearer_token"
+    url   = "%[2]s"
+  }
+}
+HCL
+					lint {
+						polling_interval = "3s"
+					}
+					protected_flows {
+						migrate_down {
+							allow = true
+						}
+					}
+				}`, devURL, srv.URL, dbURL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("atlas_migration.hello", "status.current", "2"),
+					func(*terraform.State) error {
+						if len(polls) != 2 {
+							return fmt.Errorf("expected 2 approval checks, got %d", len(polls))
+						}
+						if d := polls[1].Sub(polls[0]); d < 3*time.Second {
+							return fmt.Errorf("expected the approval checks to be 3s apart, got %s", d)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "atlas_migration" "hello" {
+					url = "%[1]s"
+					dir = "atlas://test?tag=tag1"
+					lint {
+						polling_interval = "10m"
+					}
+				}`, dbURL),
+				ExpectError: regexp.MustCompile("not a valid duration between 100ms and 5m"),
+			},
+		},
+	})
 }
 
 func newFooProvider(name, resource string) func() (*schema.Provider, error) {
@@ -1101,3 +1758,17 @@ func newFooProvider(name, resource string) func() (*schema.Provider, error) {
 		}, nil
 	}
 }
+
+// localMigrationDir writes the given name and content pairs to a temporary
+// migration directory, along with its atlas.sum file, and returns its URL.
+func localMigrationDir(t *testing.T, files ...string) string {
+	dir, err := migrate.NewLocalDir(t.TempDir())
+	require.NoError(t, err)
+	for i := 0; i < len(files); i += 2 {
+		require.NoError(t, dir.WriteFile(files[i], []byte(files[i+1])))
+	}
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+	return "file://" + filepath.ToSlash(dir.Path())
+}
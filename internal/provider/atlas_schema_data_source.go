@@ -3,13 +3,16 @@ package provider
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"hash/fnv"
 	"net/url"
 	"path/filepath"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -25,12 +28,20 @@ type (
 	AtlasSchemaDataSourceModel struct {
 		DevURL    types.String `tfsdk:"dev_url"`
 		Src       types.String `tfsdk:"src"`
+		Format    types.String `tfsdk:"format"`
 		HCL       types.String `tfsdk:"hcl"`
+		SQL       types.String `tfsdk:"sql"`
 		ID        types.String `tfsdk:"id"`
-		Variables types.Map    `tfsdk:"variables"`
+		Variables types.String `tfsdk:"variables"`
 	}
 )
 
+// Output formats used by the format attribute.
+const (
+	FormatHCL = "hcl"
+	FormatSQL = "sql"
+)
+
 // Ensure provider defined types fully satisfy framework interfaces
 var (
 	_ datasource.DataSourceWithValidateConfig = &AtlasSchemaDataSource{}
@@ -64,19 +75,29 @@ func (d *AtlasSchemaDataSource) Schema(_ context.Context, _ datasource.SchemaReq
 				Description: "The schema definition of the database. This attribute can be HCL schema or an URL to HCL/SQL file.",
 				Required:    true,
 			},
+			"format": schema.StringAttribute{
+				Description: "The output format of the normalized schema. One of `hcl` (default) or `sql`",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(FormatHCL, FormatSQL),
+				},
+			},
 			// the HCL in a predicted, and ordered format see https://atlasgo.io/cli/dev-database
 			"hcl": schema.StringAttribute{
-				Description: "The normalized form of the HCL",
+				Description: "The normalized form of the HCL. Null if format is `sql`",
+				Computed:    true,
+			},
+			"sql": schema.StringAttribute{
+				Description: "The normalized schema as SQL DDL statements. Null unless format is `sql`",
 				Computed:    true,
 			},
 			"id": schema.StringAttribute{
 				Description: "The ID of this resource",
 				Computed:    true,
 			},
-			"variables": schema.MapAttribute{
-				Description: "The map of variables used in the HCL.",
+			"variables": schema.StringAttribute{
+				Description: "Stringify JSON object containing variables to be used in the HCL.",
 				Optional:    true,
-				ElementType: types.StringType,
 			},
 		},
 	}
@@ -110,20 +131,17 @@ func (d *AtlasSchemaDataSource) Read(ctx context.Context, req datasource.ReadReq
 		// so we don't do anything.
 		data.ID = types.StringValue(hclID(nil))
 		data.HCL = types.StringNull()
+		data.SQL = types.StringNull()
 		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 	var vars atlas.Vars2
-	if !data.Variables.IsNull() {
-		varsRaw := make(map[string]string)
-		resp.Diagnostics.Append(data.Variables.ElementsAs(ctx, &varsRaw, false)...)
-		if resp.Diagnostics.HasError() {
+	if v := data.Variables.ValueString(); v != "" {
+		if err := json.Unmarshal([]byte(v), &vars); err != nil {
+			resp.Diagnostics.AddError("Generate config failure",
+				fmt.Sprintf("Failed to parse variables: %s", err.Error()))
 			return
 		}
-		vars = make(atlas.Vars2, len(varsRaw))
-		for k, v := range varsRaw {
-			vars[k] = v
-		}
 	}
 	cfg, wd, err := data.Workspace(ctx, &d.ProviderData)
 	if err != nil {
@@ -145,18 +163,27 @@ func (d *AtlasSchemaDataSource) Read(ctx context.Context, req datasource.ReadReq
 		)
 		return
 	}
-	hcl, err := c.SchemaInspect(ctx, &atlas.SchemaInspectParams{
+	params := &atlas.SchemaInspectParams{
 		Env:  cfg.EnvName,
 		Vars: vars,
-	})
+	}
+	if data.Format.ValueString() == FormatSQL {
+		params.Format = "{{ sql . }}"
+	}
+	out, err := c.SchemaInspect(ctx, params)
 	if err != nil {
 		resp.Diagnostics.AddError("Inspect Error",
 			fmt.Sprintf("Unable to inspect given source, got error: %s", err),
 		)
 		return
 	}
-	data.HCL = types.StringValue(hcl)
-	data.ID = types.StringValue(hclID([]byte(data.HCL.ValueString())))
+	data.HCL, data.SQL = types.StringNull(), types.StringNull()
+	if data.Format.ValueString() == FormatSQL {
+		data.SQL = types.StringValue(out)
+	} else {
+		data.HCL = types.StringValue(out)
+	}
+	data.ID = types.StringValue(hclID([]byte(out)))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -0,0 +1,282 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	atlas "ariga.io/atlas-go-sdk/atlasexec"
+)
+
+type (
+	// AtlasSchemaDiffDataSource defines the data source implementation.
+	AtlasSchemaDiffDataSource struct {
+		ProviderData
+	}
+	// AtlasSchemaDiffDataSourceModel describes the data source data model.
+	AtlasSchemaDiffDataSourceModel struct {
+		From   types.String `tfsdk:"from"`
+		To     types.String `tfsdk:"to"`
+		DevURL types.String `tfsdk:"dev_url"`
+
+		SQL        types.String `tfsdk:"sql"`
+		Statements types.List   `tfsdk:"statements"`
+		ID         types.String `tfsdk:"id"`
+	}
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource                   = &AtlasSchemaDiffDataSource{}
+	_ datasource.DataSourceWithConfigure      = &AtlasSchemaDiffDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &AtlasSchemaDiffDataSource{}
+)
+
+// NewAtlasSchemaDiffDataSource returns a new AtlasSchemaDiffDataSource.
+func NewAtlasSchemaDiffDataSource() datasource.DataSource {
+	return &AtlasSchemaDiffDataSource{}
+}
+
+// Metadata implements datasource.DataSource.
+func (d *AtlasSchemaDiffDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema_diff"
+}
+
+// Configure implements datasource.DataSourceWithConfigure.
+func (d *AtlasSchemaDiffDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	resp.Diagnostics.Append(d.configure(req.ProviderData)...)
+}
+
+// Schema implements datasource.DataSource.
+func (d *AtlasSchemaDiffDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data source computes the SQL statements needed to migrate a database " +
+			"to the desired schema, without applying them.",
+		Attributes: map[string]schema.Attribute{
+			"from": schema.StringAttribute{
+				Description: "The current schema. This attribute can be an URL to a database, HCL schema or an URL to HCL file. " +
+					"If it is not a database, the schema is loaded into the dev-db to compute the diff.",
+				Required:  true,
+				Sensitive: true,
+			},
+			"to": schema.StringAttribute{
+				Description: "The desired schema. This attribute can be HCL schema or an URL to HCL/SQL file or a database.",
+				Required:    true,
+			},
+			"dev_url": schema.StringAttribute{
+				Description: "The url of the dev-db see https://atlasgo.io/cli/url",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"sql": schema.StringAttribute{
+				Description: "The SQL statements of the diff, separated by newlines",
+				Computed:    true,
+			},
+			"statements": schema.ListAttribute{
+				Description: "The SQL statements of the diff",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"id": schema.StringAttribute{
+				Description: "The ID of this data source",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// ValidateConfig implements datasource.DataSourceWithValidateConfig.
+func (d *AtlasSchemaDiffDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data AtlasSchemaDiffDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(d.validate(ctx, &data)...)
+}
+
+// Read implements datasource.DataSource.
+func (d *AtlasSchemaDiffDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AtlasSchemaDiffDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	cfg, wd, err := data.Workspace(ctx, &d.ProviderData)
+	if err != nil {
+		resp.Diagnostics.AddError("Generate config failure",
+			fmt.Sprintf("Failed to create workspace: %s", err.Error()))
+		return
+	}
+	defer func() {
+		if err := wd.Close(); err != nil {
+			tflog.Debug(ctx, "Failed to cleanup working directory", map[string]any{
+				"error": err,
+			})
+		}
+	}()
+	c, err := d.Client(wd.Path(), cfg.Cloud)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to create client, got error: %s", err),
+		)
+		return
+	}
+	var result *atlas.SchemaApply
+	if data.fromDatabase() {
+		result, err = c.SchemaApply(ctx, &atlas.SchemaApplyParams{
+			Env:    cfg.EnvName,
+			DryRun: true,
+		})
+	} else {
+		var from string
+		if from, err = data.fromSource(); err == nil {
+			result, err = devDiff(ctx, c, wd, cfg.Env.URL, from, cfg.Env.Source)
+		}
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Atlas Plan Error",
+			fmt.Sprintf("Unable to generate migration plan, got error: %s", err),
+		)
+		return
+	}
+	stmts := []string{}
+	if f := result.Applied; f != nil && len(f.Applied) > 0 {
+		stmts = f.Applied
+	}
+	data.SQL = types.StringValue(strings.Join(stmts, "\n"))
+	var diags diag.Diagnostics
+	data.Statements, diags = types.ListValueFrom(ctx, types.StringType, stmts)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	data.ID = types.StringValue(hclID([]byte(data.SQL.ValueString())))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// devDiff computes the diff from a schema that is not a database, by
+// loading it into the dev-db. As the dev-db cannot be used to normalize
+// the desired schema at the same time, an HCL desired schema is loaded
+// and inspected first. The dev-db is cleaned when done.
+func devDiff(ctx context.Context, c AtlasExec, wd *atlas.WorkingDir, devURL, from, to string) (_ *atlas.SchemaApply, err error) {
+	clean := func() error {
+		_, err := c.SchemaClean(ctx, &atlas.SchemaCleanParams{
+			URL:         devURL,
+			AutoApprove: true,
+		})
+		return err
+	}
+	defer func() {
+		if cerr := clean(); cerr != nil && err == nil {
+			err = fmt.Errorf("clean the dev-db: %w", cerr)
+		}
+	}()
+	if u, err := url.Parse(to); err != nil || u.Scheme == SchemaTypeFile {
+		if _, err = c.SchemaApply(ctx, &atlas.SchemaApplyParams{
+			URL:         devURL,
+			To:          to,
+			AutoApprove: true,
+		}); err != nil {
+			return nil, fmt.Errorf("load the desired schema into the dev-db: %w", err)
+		}
+		hcl, err := c.SchemaInspect(ctx, &atlas.SchemaInspectParams{
+			URL: devURL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err = clean(); err != nil {
+			return nil, fmt.Errorf("clean the dev-db: %w", err)
+		}
+		if _, err = wd.WriteFile("to.hcl", []byte(hcl)); err != nil {
+			return nil, err
+		}
+		to = "file://to.hcl"
+	}
+	if _, err = c.SchemaApply(ctx, &atlas.SchemaApplyParams{
+		URL:         devURL,
+		To:          from,
+		AutoApprove: true,
+	}); err != nil {
+		return nil, fmt.Errorf("load the current schema into the dev-db: %w", err)
+	}
+	return c.SchemaApply(ctx, &atlas.SchemaApplyParams{
+		URL:    devURL,
+		To:     to,
+		DryRun: true,
+	})
+}
+
+// fromDatabase reports if the from attribute is a database URL.
+func (d *AtlasSchemaDiffDataSourceModel) fromDatabase() bool {
+	u, err := url.Parse(filepath.ToSlash(d.From.ValueString()))
+	return err == nil && u.Scheme != "" && u.Scheme != SchemaTypeFile
+}
+
+// fromSource returns the URL of the from schema, when it is not a database.
+// HCL schemas are written to the from.hcl file of the working directory.
+func (d *AtlasSchemaDiffDataSourceModel) fromSource() (string, error) {
+	if u, err := url.Parse(filepath.ToSlash(d.From.ValueString())); err == nil && u.Scheme == SchemaTypeFile {
+		return absoluteFileURL(d.From.ValueString())
+	}
+	return "file://from.hcl", nil
+}
+
+func (d *AtlasSchemaDiffDataSourceModel) Workspace(_ context.Context, p *ProviderData) (*projectConfig, *atlas.WorkingDir, error) {
+	devURL := defaultString(d.DevURL, p.DevURL)
+	cfg := &projectConfig{
+		Cloud:   cloudConfig(p.Cloud),
+		EnvName: "tf",
+		Env: &envConfig{
+			DevURL: devURL,
+			Source: "file://schema.hcl",
+		},
+	}
+	opts := []atlas.Option{atlas.WithAtlasHCL(cfg.Render)}
+	switch {
+	case d.fromDatabase():
+		dbURL, err := absoluteSqliteURL(d.From.ValueString())
+		if err != nil {
+			return nil, nil, err
+		}
+		cfg.Env.URL = dbURL
+	case devURL == "":
+		return nil, nil, errors.New("the dev_url is required when from is not a database URL")
+	default:
+		// The from schema is loaded into the dev-db.
+		cfg.Env.URL = devURL
+		if u, err := url.Parse(filepath.ToSlash(d.From.ValueString())); err != nil || u.Scheme == "" {
+			opts = append(opts, func(wd *atlas.WorkingDir) error {
+				_, err := wd.WriteFile("from.hcl", []byte(d.From.ValueString()))
+				return err
+			})
+		}
+	}
+	switch u, err := url.Parse(filepath.ToSlash(d.To.ValueString())); {
+	case err == nil && u.Scheme == SchemaTypeFile:
+		if cfg.Env.Source, err = absoluteFileURL(d.To.ValueString()); err != nil {
+			return nil, nil, err
+		}
+	case err == nil && u.Scheme != "":
+		cfg.Env.Source = d.To.ValueString()
+	default:
+		opts = append(opts, func(wd *atlas.WorkingDir) error {
+			_, err := wd.WriteFile("schema.hcl", []byte(d.To.ValueString()))
+			return err
+		})
+	}
+	wd, err := atlas.NewWorkingDir(opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	return cfg, wd, nil
+}
@@ -0,0 +1,107 @@
+package provider_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccSchemaDiffDataSource(t *testing.T) {
+	tempSchemas(t, mysqlURL, "test_diff")
+	tempSchemas(t, mysqlDevURL, "test_diff")
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`data "atlas_schema_diff" "market" {
+					from    = "%[1]s/test_diff"
+					dev_url = "%[2]s/test_diff"
+					to      = <<-EOT
+					schema "test_diff" {}
+					table "foo" {
+						schema = schema.test_diff
+						column "id" {
+							type = int
+						}
+					}
+					EOT
+				}`, mysqlURL, mysqlDevURL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.atlas_schema_diff.market", "statements.#", "1"),
+					resource.TestMatchResourceAttr("data.atlas_schema_diff.market", "statements.0", regexp.MustCompile("^CREATE TABLE `foo`")),
+					resource.TestMatchResourceAttr("data.atlas_schema_diff.market", "sql", regexp.MustCompile("^CREATE TABLE `foo`")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSchemaDiffDataSource_FromHCL(t *testing.T) {
+	tempSchemas(t, mysqlDevURL, "test_diff_hcl")
+	from := `
+schema "test_diff_hcl" {}
+table "foo" {
+  schema = schema.test_diff_hcl
+  column "id" {
+    type = int
+  }
+}
+`
+	fromFile := filepath.Join(t.TempDir(), "from.hcl")
+	require.NoError(t, os.WriteFile(fromFile, []byte(from), 0644))
+	to := `<<-EOT
+	schema "test_diff_hcl" {}
+	table "foo" {
+		schema = schema.test_diff_hcl
+		column "id" {
+			type = int
+		}
+		column "name" {
+			type = varchar(255)
+		}
+	}
+	EOT`
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`data "atlas_schema_diff" "market" {
+					from    = <<-EOT
+					%[1]s
+					EOT
+					dev_url = "%[2]s/test_diff_hcl"
+					to      = %[3]s
+				}`, from, mysqlDevURL, to),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.atlas_schema_diff.market", "statements.#", "1"),
+					resource.TestMatchResourceAttr("data.atlas_schema_diff.market", "statements.0", regexp.MustCompile("^ALTER TABLE `foo` ADD COLUMN `name`")),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`data "atlas_schema_diff" "market" {
+					from    = "file://%[1]s"
+					dev_url = "%[2]s/test_diff_hcl"
+					to      = %[3]s
+				}`, fromFile, mysqlDevURL, to),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.atlas_schema_diff.market", "statements.#", "1"),
+					resource.TestMatchResourceAttr("data.atlas_schema_diff.market", "statements.0", regexp.MustCompile("^ALTER TABLE `foo` ADD COLUMN `name`")),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`data "atlas_schema_diff" "market" {
+					from = "file://%[1]s"
+					to   = %[2]s
+				}`, fromFile, to),
+				ExpectError: regexp.MustCompile("the dev_url is required when from is not a database URL"),
+			},
+		},
+	})
+}
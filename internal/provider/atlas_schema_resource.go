@@ -1,23 +1,42 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/zclconf/go-cty/cty"
 
 	atlas "ariga.io/atlas-go-sdk/atlasexec"
 )
@@ -34,9 +53,61 @@ type (
 		URL     types.String `tfsdk:"url"`
 		DevURL  types.String `tfsdk:"dev_url"`
 		Exclude types.List   `tfsdk:"exclude"`
+		Include types.List   `tfsdk:"include"`
 		TxMode  types.String `tfsdk:"tx_mode"`
+
+		ConfigFile types.String `tfsdk:"config_file"`
+
+		SchemaCleanupOnDestroy types.String `tfsdk:"schema_cleanup_on_destroy"`
+		SkipErrorPatterns      types.List   `tfsdk:"skip_error_patterns"`
+		SkipTables             types.List   `tfsdk:"skip_tables"`
+		StrictMode             types.Bool   `tfsdk:"strict_mode"`
+		ManagedObjects         types.Map    `tfsdk:"managed_objects"`
+		ManagedSchemas         types.List   `tfsdk:"managed_schemas"`
+		ManagedViews           types.List   `tfsdk:"managed_views"`
+		SchemaObjectCounts     types.Map    `tfsdk:"schema_object_counts"`
+		ManagedFunctions       types.List   `tfsdk:"managed_functions"`
+		ManagedProcedures      types.List   `tfsdk:"managed_procedures"`
+		IntrospectSchemas      types.List   `tfsdk:"introspect_schemas"`
+		AutoBaseline           types.Bool   `tfsdk:"auto_baseline"`
+		BaselineExclude        types.List   `tfsdk:"baseline_exclude"`
+		DiffPolicyFile         types.String `tfsdk:"diff_policy_file"`
+		EffectiveExclude       types.List   `tfsdk:"effective_exclude"`
+		ApplyOnCreateOnly      types.Bool   `tfsdk:"apply_on_create_only"`
+		WarningAsError         types.Bool   `tfsdk:"warning_as_error"`
+		SkipNormalize          types.Bool   `tfsdk:"skip_normalize"`
+		RetryOnLock            types.Bool   `tfsdk:"retry_on_lock"`
+		TimeoutOnLock          types.String `tfsdk:"timeout_on_lock"`
+		LockRetryInterval      types.String `tfsdk:"lock_retry_interval"`
+		HCLValidationScript    types.String `tfsdk:"hcl_validation_script"`
+		LastPlanSQL            types.String `tfsdk:"last_plan_sql"`
+		PlanSQL                types.String `tfsdk:"plan_sql"`
+		GitCommitSHA           types.String `tfsdk:"git_commit_sha"`
+		SchemaFilePath         types.String `tfsdk:"schema_file_path"`
+		SchemaSourceChecksum   types.String `tfsdk:"schema_source_checksum"`
+		PreApplySQL            types.String `tfsdk:"pre_apply_sql"`
+		PostApplySQL           types.String `tfsdk:"post_apply_sql"`
+		ForceDestroy           types.Bool   `tfsdk:"force_destroy"`
+		// Sources
+		HCLFromGit *GitSource `tfsdk:"hcl_from_git"`
 		// Policies
-		Diff *Diff `tfsdk:"diff"`
+		Diff  *Diff  `tfsdk:"diff"`
+		Lint  *Lint  `tfsdk:"lint"`
+		Retry *Retry `tfsdk:"retry"`
+
+		Timeouts timeouts.Value `tfsdk:"timeouts"`
+	}
+	// GitSource defines a file in a Git repository to read the schema from.
+	GitSource struct {
+		Repo types.String `tfsdk:"repo"`
+		Ref  types.String `tfsdk:"ref"`
+		Path types.String `tfsdk:"path"`
+	}
+	// Retry defines the policy for retrying a failed apply.
+	Retry struct {
+		MaxAttempts types.Int64  `tfsdk:"max_attempts"`
+		Interval    types.String `tfsdk:"interval"`
+		RetryOn     types.String `tfsdk:"retry_on"`
 	}
 	// Diff defines the diff policies to apply when planning schema changes.
 	Diff struct {
@@ -64,18 +135,44 @@ type (
 		AddForeignKey    types.Bool `tfsdk:"add_foreign_key"`
 		DropForeignKey   types.Bool `tfsdk:"drop_foreign_key"`
 		ModifyForeignKey types.Bool `tfsdk:"modify_foreign_key"`
+		AddCheck         types.Bool `tfsdk:"add_check"`
+		DropCheck        types.Bool `tfsdk:"drop_check"`
+		AddView          types.Bool `tfsdk:"add_view"`
+		DropView         types.Bool `tfsdk:"drop_view"`
+		ModifyView       types.Bool `tfsdk:"modify_view"`
+		AddSequence      types.Bool `tfsdk:"add_sequence"`
+		DropSequence     types.Bool `tfsdk:"drop_sequence"`
+		ModifySequence   types.Bool `tfsdk:"modify_sequence"`
 	}
 )
 
+// Cleanup modes used by the schema_cleanup_on_destroy attribute.
+const (
+	CleanupAll        = "all"
+	CleanupTablesOnly = "tables_only"
+	CleanupNone       = "none"
+)
+
 // Ensure provider defined types fully satisfy framework interfaces
 var (
 	_ resource.Resource                   = &AtlasSchemaResource{}
 	_ resource.ResourceWithModifyPlan     = &AtlasSchemaResource{}
 	_ resource.ResourceWithConfigure      = &AtlasSchemaResource{}
 	_ resource.ResourceWithValidateConfig = &AtlasSchemaResource{}
+	_ resource.ResourceWithImportState    = &AtlasSchemaResource{}
 )
 
 var (
+	managedObjectsType = types.MapType{
+		ElemType: types.ListType{ElemType: types.StringType},
+	}
+	managedRoutineType = types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"name":      types.StringType,
+			"schema":    types.StringType,
+			"signature": types.StringType,
+		},
+	}
 	diffBlock = schema.SingleNestedBlock{
 		Blocks: map[string]schema.Block{
 			"concurrent_index": schema.SingleNestedBlock{
@@ -103,16 +200,63 @@ var (
 					"add_foreign_key":    boolOptional("Whether to skip adding foreign keys"),
 					"drop_foreign_key":   boolOptional("Whether to skip dropping foreign keys"),
 					"modify_foreign_key": boolOptional("Whether to skip modifying foreign keys"),
+					"add_check":          boolOptional("Whether to skip adding check constraints"),
+					"drop_check":         boolOptional("Whether to skip dropping check constraints"),
+					"add_view":           boolOptional("Whether to skip adding views"),
+					"drop_view":          boolOptional("Whether to skip dropping views"),
+					"modify_view":        boolOptional("Whether to skip modifying views"),
+					"add_sequence":       boolOptional("Whether to skip adding sequences"),
+					"drop_sequence":      boolOptional("Whether to skip dropping sequences"),
+					"modify_sequence":    boolOptional("Whether to skip modifying sequences"),
 				},
 			},
 		},
 	}
+	retryBlock = schema.SingleNestedBlock{
+		Description: "The policy for retrying the apply on transient errors, such as network failures or deadlocks",
+		Attributes: map[string]schema.Attribute{
+			"max_attempts": schema.Int64Attribute{
+				Description: "The maximum number of attempts, including the first one. Default: 3",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"interval": schema.StringAttribute{
+				Description: "The time to wait between attempts. " +
+					"A string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration). Default: 5s",
+				Optional: true,
+			},
+			"retry_on": schema.StringAttribute{
+				Description: "A regular expression matching the errors to retry on. If unset, all errors are retried",
+				Optional:    true,
+			},
+		},
+	}
 )
 
 func (m AtlasSchemaResourceModel) Clone() *AtlasSchemaResourceModel {
 	return &m
 }
 
+// EmptySchemas returns a copy of the model with an HCL containing only
+// the schema blocks of the original one. Applying it drops all the objects
+// in the schemas, but keeps the schemas themselves.
+func (m AtlasSchemaResourceModel) EmptySchemas() (*AtlasSchemaResourceModel, error) {
+	f, diags := hclwrite.ParseConfig([]byte(m.HCL.ValueString()), "schema.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	body := f.Body()
+	for _, blk := range body.Blocks() {
+		if blk.Type() != "schema" {
+			body.RemoveBlock(blk)
+		}
+	}
+	m.HCL = types.StringValue(string(f.Bytes()))
+	return &m, nil
+}
+
 // NewAtlasSchemaResource returns a new AtlasSchemaResource.
 func NewAtlasSchemaResource() resource.Resource {
 	return &AtlasSchemaResource{}
@@ -134,13 +278,48 @@ func (r *AtlasSchemaResource) Schema(ctx context.Context, _ resource.SchemaReque
 			"using an HCL file describing the wanted state of the database. " +
 			"See https://atlasgo.io/",
 		Blocks: map[string]schema.Block{
-			"diff": diffBlock,
+			"diff":  diffBlock,
+			"lint":  lintBlock,
+			"retry": retryBlock,
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+				CreateDescription: `Timeout defaults to 20 mins. A string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) ` +
+					`consisting of numbers and unit suffixes, such as "30s" or "2h45m". Valid time units are ` +
+					`"s" (seconds), "m" (minutes), "h" (hours).`,
+				UpdateDescription: `Timeout defaults to 20 mins. A string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) ` +
+					`consisting of numbers and unit suffixes, such as "30s" or "2h45m". Valid time units are ` +
+					`"s" (seconds), "m" (minutes), "h" (hours).`,
+				DeleteDescription: `Timeout defaults to 20 mins. A string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) ` +
+					`consisting of numbers and unit suffixes, such as "30s" or "2h45m". Valid time units are ` +
+					`"s" (seconds), "m" (minutes), "h" (hours).`,
+			}),
+			"hcl_from_git": schema.SingleNestedBlock{
+				Description: "Read the schema definition from a file in a Git repository, instead of the `hcl` attribute",
+				Attributes: map[string]schema.Attribute{
+					"repo": schema.StringAttribute{
+						Description: "The URL or path of the Git repository",
+						Optional:    true,
+					},
+					"ref": schema.StringAttribute{
+						Description: "The branch, tag or commit to read the file from. Default: HEAD",
+						Optional:    true,
+					},
+					"path": schema.StringAttribute{
+						Description: "The path of the HCL file in the repository",
+						Optional:    true,
+					},
+				},
+			},
 		},
 		Attributes: map[string]schema.Attribute{
 			"hcl": schema.StringAttribute{
 				Description: "The schema definition for the database " +
-					"(preferably normalized - see `atlas_schema` data source)",
-				Required: true,
+					"(preferably normalized - see `atlas_schema` data source). " +
+					"One of `hcl`, `hcl_from_git` or `schema_file_path` must be set",
+				Optional: true,
+				Computed: true,
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
 				},
@@ -160,6 +339,24 @@ func (r *AtlasSchemaResource) Schema(ctx context.Context, _ resource.SchemaReque
 				ElementType: types.StringType,
 				Optional:    true,
 			},
+			"include": schema.ListAttribute{
+				Description: "Limit the management to resources matching the given glob patterns. " +
+					"Cannot be used together with `introspect_schemas`",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"skip_tables": schema.ListAttribute{
+				Description: "The names of tables to exclude from management. Unlike `exclude`, the names are matched " +
+					"exactly. For URLs connected to a database (realm), use the `<schema>.<table>` form",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"introspect_schemas": schema.ListAttribute{
+				Description: "Limit the inspection of the database during refresh to the given schemas. " +
+					"Useful to speed up the refresh of databases with many schemas when using a realm URL",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
 			"tx_mode": schema.StringAttribute{
 				Description: "The transaction mode to use when applying the schema. See https://atlasgo.io/versioned/apply#transaction-configuration",
 				Optional:    true,
@@ -167,6 +364,138 @@ func (r *AtlasSchemaResource) Schema(ctx context.Context, _ resource.SchemaReque
 					stringvalidator.OneOf("file", "all", "none"),
 				},
 			},
+			"schema_cleanup_on_destroy": schema.StringAttribute{
+				Description: "Controls what is removed from the database when the resource is destroyed. " +
+					"One of `all` (default), `tables_only` (keeps the schemas) or `none` (leaves the database untouched)",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(CleanupAll, CleanupTablesOnly, CleanupNone),
+				},
+			},
+			"force_destroy": boolOptional("When enabled, the schemas managed by the resource are dropped on destroy, " +
+				"after their objects were removed. Supported for MySQL, MariaDB and PostgreSQL"),
+			"warning_as_error": boolOptional("When enabled, the warnings reported for the resource, e.g. the Atlas plan, are reported as errors"),
+			"apply_on_create_only": boolOptional("When enabled, the schema is applied only when the resource is created. " +
+				"Later changes to `hcl` are stored in the state without being applied to the database"),
+			"auto_baseline": boolOptional("When enabled, objects that exist in the database but are not defined in `hcl` " +
+				"are kept on the first run instead of failing the plan. Only the changes from the existing database " +
+				"to the objects defined in `hcl` are applied, and the kept objects are excluded from later runs (see `baseline_exclude`)"),
+			"retry_on_lock": boolOptional("When enabled, applying the schema is retried if it fails on a lock wait timeout"),
+			"timeout_on_lock": schema.StringAttribute{
+				Description: "The total time to retry applying the schema on lock wait timeouts, when `retry_on_lock` is enabled. " +
+					"A string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration). Default: 1m",
+				Optional: true,
+			},
+			"lock_retry_interval": schema.StringAttribute{
+				Description: "The time to wait between retries on lock wait timeouts, when `retry_on_lock` is enabled. " +
+					"A string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration). Default: 5s",
+				Optional: true,
+			},
+			"skip_normalize": boolOptional("When enabled, the `hcl` is applied as is, without being normalized " +
+				"on the dev database. Use it only with a normalized schema, e.g. from the `atlas_schema` data source"),
+			"strict_mode": boolOptional("When enabled, changes made to the database outside of Terraform " +
+				"are reported as errors during refresh instead of being read into the state"),
+			"diff_policy_file": schema.StringAttribute{
+				Description: "A file:// URL of an HCL file containing a `diff` block. The policy is merged with " +
+					"the inline `diff` block, which takes precedence",
+				Optional: true,
+			},
+			"hcl_validation_script": schema.StringAttribute{
+				Description: "The path of a local script used to validate the `hcl` attribute. The HCL is piped to " +
+					"the script's stdin, and a non-zero exit code fails the validation with the script's stderr as the error",
+				Optional: true,
+			},
+			"pre_apply_sql": schema.StringAttribute{
+				Description: "SQL script executed on the database before the schema is applied. The script is either " +
+					"SQL statements or a file:// URL, and runs in its own transaction. Supported for MySQL, MariaDB, PostgreSQL and SQLite",
+				Optional: true,
+			},
+			"post_apply_sql": schema.StringAttribute{
+				Description: "SQL script executed on the database after the schema was applied successfully. The script is either " +
+					"SQL statements or a file:// URL, and runs in its own transaction. Supported for MySQL, MariaDB, PostgreSQL and SQLite",
+				Optional: true,
+			},
+			"skip_error_patterns": schema.ListAttribute{
+				Description: "A list of regular expressions. Errors returned while applying the schema that match " +
+					"one of the patterns are reported as warnings instead of failing the apply",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"plan_sql": schema.StringAttribute{
+				Description: "The SQL statements that are planned to be executed on the database, or null if there are no pending changes",
+				Computed:    true,
+			},
+			"last_plan_sql": schema.StringAttribute{
+				Description: "The SQL statements of the most recent plan that contained changes",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"managed_objects": schema.MapAttribute{
+				Description: "The objects managed by the resource, grouped by their type. " +
+					"For example: `{ table = [\"users\"], view = [\"user_summary\"] }`",
+				ElementType: managedObjectsType.ElemType,
+				Computed:    true,
+			},
+			"baseline_exclude": schema.ListAttribute{
+				Description: "The exclude patterns of the objects kept by `auto_baseline` on the first run. " +
+					"These objects are not managed by the resource",
+				ElementType: types.StringType,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"effective_exclude": schema.ListAttribute{
+				Description: "The database objects filtered out by the `exclude` patterns, in the form of `<type>.<name>`",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"managed_schemas": schema.ListAttribute{
+				Description: "The names of the schemas managed by the resource",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"managed_views": schema.ListAttribute{
+				Description: "The names of the views managed by the resource",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"managed_functions": schema.ListAttribute{
+				Description: "The functions managed by the resource",
+				ElementType: managedRoutineType,
+				Computed:    true,
+			},
+			"managed_procedures": schema.ListAttribute{
+				Description: "The procedures managed by the resource",
+				ElementType: managedRoutineType,
+				Computed:    true,
+			},
+			"schema_object_counts": schema.MapAttribute{
+				Description: "The number of objects managed by the resource, keyed by their type (e.g. `tables`, `views`, `indexes`, `foreign_keys`)",
+				ElementType: types.Int64Type,
+				Computed:    true,
+			},
+			"config_file": schema.StringAttribute{
+				Description: "The path of a local atlas.hcl file to merge the generated configuration into. " +
+					"The resource uses the `tf` environment. Relative paths are resolved from the working directory of Terraform. " +
+					"If unset, the file set by the ATLAS_CONFIG environment variable is used",
+				Optional: true,
+			},
+			"schema_file_path": schema.StringAttribute{
+				Description: "The path of a local HCL file to read the schema definition from, instead of the `hcl` attribute. " +
+					"Relative paths are resolved from the working directory of Terraform",
+				Optional: true,
+			},
+			"schema_source_checksum": schema.StringAttribute{
+				Description: "The SHA-256 checksum of the schema file, when `schema_file_path` or `hcl_from_git` is set",
+				Computed:    true,
+			},
+			"git_commit_sha": schema.StringAttribute{
+				Description: "The commit the schema was read from, when `hcl_from_git` is set",
+				Computed:    true,
+			},
 			"id": schema.StringAttribute{
 				Description: "The ID of this resource",
 				Computed:    true,
@@ -185,10 +514,45 @@ func (r *AtlasSchemaResource) Create(ctx context.Context, req resource.CreateReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if data.WarningAsError.ValueBool() {
+		defer func() { resp.Diagnostics = upgradeWarningsToErrors(resp.Diagnostics) }()
+	}
+	createTimeout, diags := data.Timeouts.Create(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+	if data.AutoBaseline.ValueBool() {
+		if data, diags = r.baseline(ctx, data); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+	if data.BaselineExclude.IsUnknown() {
+		data.BaselineExclude = types.ListNull(types.StringType)
+	}
 	resp.Diagnostics.Append(r.applySchema(ctx, data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	resp.Diagnostics.Append(r.readObjects(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.LastPlanSQL.IsUnknown() {
+		data.LastPlanSQL = types.StringNull()
+	}
+	if data.PlanSQL.IsUnknown() {
+		data.PlanSQL = types.StringNull()
+	}
+	if data.GitCommitSHA.IsUnknown() {
+		data.GitCommitSHA = types.StringNull()
+	}
+	if data.SchemaSourceChecksum.IsUnknown() {
+		data.SchemaSourceChecksum = types.StringNull()
+	}
 	data.ID = types.StringValue(urlToID(data.URL))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -204,6 +568,8 @@ func (r *AtlasSchemaResource) Read(ctx context.Context, req resource.ReadRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// The pending statements are computed again by the next plan.
+	data.PlanSQL = types.StringNull()
 	data.ID = types.StringValue(urlToID(data.URL))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -215,10 +581,36 @@ func (r *AtlasSchemaResource) Update(ctx context.Context, req resource.UpdateReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	resp.Diagnostics.Append(r.applySchema(ctx, data)...)
+	if data.WarningAsError.ValueBool() {
+		defer func() { resp.Diagnostics = upgradeWarningsToErrors(resp.Diagnostics) }()
+	}
+	updateTimeout, diags := data.Timeouts.Update(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+	if data.BaselineExclude.IsUnknown() {
+		// The resource was created without auto_baseline.
+		data.BaselineExclude = types.ListNull(types.StringType)
+	}
+	if !data.ApplyOnCreateOnly.ValueBool() {
+		resp.Diagnostics.Append(r.applySchema(ctx, data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(r.readObjects(ctx, data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if data.LastPlanSQL.IsUnknown() {
+		data.LastPlanSQL = types.StringNull()
+	}
+	if data.PlanSQL.IsUnknown() {
+		data.PlanSQL = types.StringNull()
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -229,7 +621,28 @@ func (r *AtlasSchemaResource) Delete(ctx context.Context, req resource.DeleteReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	cfg, wd, err := data.Workspace(ctx, &r.ProviderData)
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+	target := data
+	switch data.SchemaCleanupOnDestroy.ValueString() {
+	case CleanupNone:
+		// Leave the database untouched.
+		return
+	case CleanupTablesOnly:
+		empty, err := data.EmptySchemas()
+		if err != nil {
+			resp.Diagnostics.AddError("Generate config failure",
+				fmt.Sprintf("Failed to parse the schema: %s", err.Error()))
+			return
+		}
+		target = empty
+	}
+	cfg, wd, err := target.Workspace(ctx, &r.ProviderData)
 	if err != nil {
 		resp.Diagnostics.AddError("Generate config failure",
 			fmt.Sprintf("Failed to create workspace: %s", err.Error()))
@@ -249,16 +662,51 @@ func (r *AtlasSchemaResource) Delete(ctx context.Context, req resource.DeleteReq
 		)
 		return
 	}
-	_, err = c.SchemaClean(ctx, &atlas.SchemaCleanParams{
-		Env:         cfg.EnvName,
-		AutoApprove: true,
-	})
+	if target != data {
+		// Apply the schemas without their objects, so the schemas themselves
+		// are kept. Unlike applySchema, the apply scripts, the retries and the
+		// skip_error_patterns of the resource are not used on destroy.
+		_, err = c.SchemaApply(ctx, &atlas.SchemaApplyParams{
+			Env:         cfg.EnvName,
+			TxMode:      data.TxMode.ValueString(),
+			AutoApprove: true,
+		})
+	} else {
+		_, err = c.SchemaClean(ctx, &atlas.SchemaCleanParams{
+			Env:         cfg.EnvName,
+			AutoApprove: true,
+		})
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Apply Error",
 			fmt.Sprintf("Unable to apply changes, got error: %s", err),
 		)
 		return
 	}
+	if data.ForceDestroy.ValueBool() {
+		var names []string
+		if diags := data.ManagedSchemas.ElementsAs(ctx, &names, false); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		if err := dropSchemas(ctx, cfg.Env.URL, names); err != nil {
+			resp.Diagnostics.AddError("Apply Error",
+				fmt.Sprintf("Unable to drop the schemas, got error: %s", err),
+			)
+		}
+	}
+}
+
+// ImportState implements resource.ResourceWithImportState.
+// The import ID is the URL of the database, and the schema
+// is inspected from the database by the following Read.
+func (r *AtlasSchemaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if _, err := url.Parse(req.ID); err != nil || req.ID == "" {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("The import ID must be the URL of the database, got: %q", req.ID))
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("url"), req, resp)
 }
 
 // ValidateConfig implements resource.ResourceWithValidateConfig.
@@ -268,6 +716,152 @@ func (r AtlasSchemaResource) ValidateConfig(ctx context.Context, req resource.Va
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if plan.WarningAsError.ValueBool() {
+		defer func() { resp.Diagnostics = upgradeWarningsToErrors(resp.Diagnostics) }()
+	}
+	for i, v := range plan.SkipErrorPatterns.Elements() {
+		if p, ok := v.(types.String); ok && !p.IsUnknown() && !p.IsNull() {
+			if _, err := regexp.Compile(p.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("skip_error_patterns").AtListIndex(i),
+					"Invalid skip_error_patterns",
+					fmt.Sprintf("The pattern %q is not a valid regular expression: %s", p.ValueString(), err),
+				)
+			}
+		}
+	}
+	var sources int
+	for _, set := range []bool{!plan.HCL.IsNull(), plan.HCLFromGit != nil, !plan.SchemaFilePath.IsNull()} {
+		if set {
+			sources++
+		}
+	}
+	switch g := plan.HCLFromGit; {
+	case sources == 0:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hcl"),
+			"Missing schema definition",
+			"One of hcl, hcl_from_git or schema_file_path must be set",
+		)
+	case sources > 1:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hcl"),
+			"Conflicting schema definition",
+			"Only one of hcl, hcl_from_git or schema_file_path can be set",
+		)
+	case g != nil && (g.Repo.IsNull() || g.Path.IsNull()):
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hcl_from_git"),
+			"Invalid hcl_from_git",
+			"Both repo and path must be set",
+		)
+	case g != nil && strings.HasPrefix(g.Repo.ValueString(), "-"):
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hcl_from_git").AtName("repo"),
+			"Invalid hcl_from_git",
+			"The repo must not start with a dash",
+		)
+	}
+	for _, a := range []struct {
+		name string
+		v    types.String
+	}{
+		{"timeout_on_lock", plan.TimeoutOnLock},
+		{"lock_retry_interval", plan.LockRetryInterval},
+	} {
+		if a.v.IsNull() || a.v.IsUnknown() {
+			continue
+		}
+		if d, err := time.ParseDuration(a.v.ValueString()); err != nil || d <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(a.name),
+				fmt.Sprintf("Invalid %s", a.name),
+				fmt.Sprintf("The value %q is not a valid positive duration", a.v.ValueString()),
+			)
+		}
+	}
+	for name, v := range map[string]types.String{"pre_apply_sql": plan.PreApplySQL, "post_apply_sql": plan.PostApplySQL} {
+		if v.IsNull() || v.IsUnknown() {
+			continue
+		}
+		if _, err := hookScript(v.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root(name), "Invalid apply script", err.Error())
+		}
+		resp.Diagnostics.Append(checkSQLClientURLs(path.Root(name), plan.URL)...)
+	}
+	if plan.ForceDestroy.ValueBool() {
+		switch u, err := url.Parse(plan.URL.ValueString()); {
+		case plan.SchemaCleanupOnDestroy.ValueString() == CleanupTablesOnly || plan.SchemaCleanupOnDestroy.ValueString() == CleanupNone:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("force_destroy"),
+				"Conflicting configuration",
+				fmt.Sprintf("force_destroy cannot be enabled when schema_cleanup_on_destroy is %q", plan.SchemaCleanupOnDestroy.ValueString()),
+			)
+		case err == nil && u.Scheme == SchemaTypeSQLite:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("force_destroy"),
+				"Unsupported configuration",
+				"force_destroy is not supported for SQLite databases",
+			)
+		case plan.DevURL.IsNull() && r.DevURL == "":
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("force_destroy"),
+				"Force destroy",
+				"force_destroy is enabled without a dev_url, which may indicate a production database. "+
+					"The schemas managed by the resource are dropped on destroy",
+			)
+		}
+		resp.Diagnostics.Append(checkSQLClientURLs(path.Root("force_destroy"), plan.URL)...)
+	}
+	resp.Diagnostics.Append(plan.Retry.validate()...)
+	if !plan.Include.IsNull() && !plan.IntrospectSchemas.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("include"),
+			"Conflicting include",
+			"include and introspect_schemas cannot be set at the same time",
+		)
+	}
+	if h := plan.HCL; !h.IsNull() && !h.IsUnknown() && !plan.SkipTables.IsUnknown() {
+		if objs, err := hclObjects(h.ValueString()); err == nil {
+			for i, v := range plan.SkipTables.Elements() {
+				n, ok := v.(types.String)
+				if !ok || n.IsNull() || n.IsUnknown() {
+					continue
+				}
+				name := n.ValueString()
+				if j := strings.LastIndexByte(name, '.'); j >= 0 {
+					name = name[j+1:]
+				}
+				if slices.Contains(objs["table"], name) {
+					resp.Diagnostics.AddAttributeWarning(
+						path.Root("skip_tables").AtListIndex(i),
+						"Skipped table is defined in the schema",
+						fmt.Sprintf("The table %q is defined in the hcl, but it is excluded from management by skip_tables", n.ValueString()),
+					)
+				}
+			}
+		}
+	}
+	if plan.SkipNormalize.ValueBool() {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("skip_normalize"),
+			"skip_normalize is enabled",
+			"The HCL is applied without normalization. If it is not normalized, e.g. by "+
+				"the atlas_schema data source, Terraform may show a perpetual diff.",
+		)
+	}
+	if s, h := plan.HCLValidationScript, plan.HCL; !s.IsNull() && !s.IsUnknown() && !h.IsUnknown() {
+		if err := validateHCL(ctx, s.ValueString(), h.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("hcl"),
+				"HCL validation failed",
+				err.Error(),
+			)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	resp.Diagnostics.Append(r.validate(ctx, &plan)...)
 }
 
@@ -283,18 +877,45 @@ func (r *AtlasSchemaResource) ModifyPlan(ctx context.Context, req resource.Modif
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	if state == nil || state.HCL.ValueString() == "" {
-		if plan == nil {
+	if (plan != nil && plan.WarningAsError.ValueBool()) || (plan == nil && state != nil && state.WarningAsError.ValueBool()) {
+		defer func() { resp.Diagnostics = upgradeWarningsToErrors(resp.Diagnostics) }()
+	}
+	if plan != nil {
+		resp.Diagnostics.Append(r.resolveSource(ctx, plan, resp)...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
-		if plan.URL.IsUnknown() {
-			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("url"))
+	}
+	if plan != nil && plan.URL.IsUnknown() {
+		// The url is computed by another resource, e.g. from a generated password,
+		// so the database cannot be inspected. The changes are planned on apply.
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("plan_sql"), types.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("last_plan_sql"), types.StringUnknown())...)
+		return
+	}
+	if state == nil || state.HCL.ValueString() == "" {
+		if plan == nil {
 			return
 		}
 		// New terraform resource will be create,
 		// do the first run check to ensure the user doesn't
 		// drops schema resources by accident
 		resp.Diagnostics.Append(r.firstRunCheck(ctx, plan)...)
+		if plan.AutoBaseline.ValueBool() && !resp.Diagnostics.HasError() {
+			// Plan the changes from the existing database.
+			based, diags := r.baseline(ctx, plan)
+			if resp.Diagnostics.Append(diags...); diags.HasError() {
+				return
+			}
+			plan = based
+		}
+	}
+	if state != nil && plan != nil && plan.ApplyOnCreateOnly.ValueBool() {
+		if !req.Plan.Raw.Equal(req.State.Raw) {
+			resp.Diagnostics.AddWarning("Apply on create only",
+				"The schema changes will not be applied to the database, because apply_on_create_only is enabled")
+		}
+		return
 	}
 	var isDelete bool
 	if plan == nil {
@@ -304,13 +925,128 @@ func (r *AtlasSchemaResource) ModifyPlan(ctx context.Context, req resource.Modif
 			// in the state, so we can safely ignore it
 			return
 		}
-		plan = state.Clone()
-		isDelete = true
+		switch state.SchemaCleanupOnDestroy.ValueString() {
+		case CleanupNone:
+			// Nothing will be executed on destroy.
+			return
+		case CleanupTablesOnly:
+			empty, err := state.EmptySchemas()
+			if err != nil {
+				resp.Diagnostics.AddError("Generate config failure",
+					fmt.Sprintf("Failed to parse the schema: %s", err.Error()))
+				return
+			}
+			plan = empty
+		default:
+			plan = state.Clone()
+			isDelete = true
+		}
 	}
-	resp.Diagnostics.Append(PrintPlanSQL(ctx, &r.ProviderData, plan, isDelete)...)
+	stmts, diags := planSQL(ctx, &r.ProviderData, plan, isDelete)
+	if resp.Diagnostics.Append(diags...); diags.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(planWarning(stmts)...)
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	planned := types.StringNull()
+	if len(stmts) > 0 {
+		planned = types.StringValue(strings.Join(stmts, "\n"))
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("last_plan_sql"), planned)...)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("plan_sql"), planned)...)
+}
+
+// resolveSource reads the schema definition from the hcl_from_git block or the
+// schema_file_path attribute, if set, and records it in the plan along with its
+// checksum. Changes to the source are then planned like changes to the hcl attribute.
+func (r *AtlasSchemaResource) resolveSource(ctx context.Context, plan *AtlasSchemaResourceModel, resp *resource.ModifyPlanResponse) (diags diag.Diagnostics) {
+	plan.GitCommitSHA, plan.SchemaSourceChecksum = types.StringNull(), types.StringNull()
+	switch g, f := plan.HCLFromGit, plan.SchemaFilePath; {
+	case g != nil:
+		if g.Repo.IsUnknown() || g.Ref.IsUnknown() || g.Path.IsUnknown() {
+			return
+		}
+		src, sha, err := g.Fetch(ctx)
+		if err != nil {
+			diags.AddAttributeError(path.Root("hcl_from_git"), "Git Error",
+				fmt.Sprintf("Unable to read the schema from Git, got error: %s", err),
+			)
+			return
+		}
+		plan.HCL, plan.GitCommitSHA = types.StringValue(src), types.StringValue(sha)
+	case !f.IsNull():
+		if f.IsUnknown() {
+			return
+		}
+		src, err := readSchemaFile(f.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("schema_file_path"), "Read Error",
+				fmt.Sprintf("Unable to read the schema file, got error: %s", err),
+			)
+			return
+		}
+		plan.HCL = types.StringValue(src)
+	default:
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root("git_commit_sha"), plan.GitCommitSHA)...)
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root("schema_source_checksum"), plan.SchemaSourceChecksum)...)
+		return
+	}
+	sum := sha256.Sum256([]byte(plan.HCL.ValueString()))
+	plan.SchemaSourceChecksum = types.StringValue(hex.EncodeToString(sum[:]))
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("hcl"), plan.HCL)...)
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("git_commit_sha"), plan.GitCommitSHA)...)
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("schema_source_checksum"), plan.SchemaSourceChecksum)...)
+	return
+}
+
+// readSchemaFile reads the schema definition from the given local file.
+func readSchemaFile(name string) (string, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(abs)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// upgradeWarningsToErrors returns a copy of the diagnostics
+// with all the warnings reported as errors.
+func upgradeWarningsToErrors(diags diag.Diagnostics) diag.Diagnostics {
+	upgraded := make(diag.Diagnostics, 0, len(diags))
+	for _, d := range diags {
+		if d.Severity() == diag.SeverityWarning {
+			if p, ok := d.(diag.DiagnosticWithPath); ok {
+				d = diag.NewAttributeErrorDiagnostic(p.Path(), d.Summary(), d.Detail())
+			} else {
+				d = diag.NewErrorDiagnostic(d.Summary(), d.Detail())
+			}
+		}
+		upgraded = append(upgraded, d)
+	}
+	return upgraded
 }
 
-func PrintPlanSQL(ctx context.Context, p *ProviderData, data *AtlasSchemaResourceModel, delete bool) (diags diag.Diagnostics) {
+// planWarning returns the "Atlas Plan" warning for the given statements.
+func planWarning(stmts []string) (diags diag.Diagnostics) {
+	if len(stmts) > 0 {
+		buf := &strings.Builder{}
+		for _, stmt := range stmts {
+			fmt.Fprintln(buf, stmt)
+		}
+		diags.AddWarning("Atlas Plan",
+			fmt.Sprintf("The following SQL statements will be executed:\n\n\n%s", buf.String()),
+		)
+	}
+	return diags
+}
+
+// planSQL returns the SQL statements that will be executed on the database.
+func planSQL(ctx context.Context, p *ProviderData, data *AtlasSchemaResourceModel, delete bool) (stmts []string, diags diag.Diagnostics) {
 	cfg, wd, err := data.Workspace(ctx, p)
 	if err != nil {
 		diags.AddError("Generate config failure",
@@ -359,19 +1095,71 @@ func PrintPlanSQL(ctx context.Context, p *ProviderData, data *AtlasSchemaResourc
 		appliedFile = result.Applied
 
 	}
-	if appliedFile != nil && len(appliedFile.Applied) > 0 {
-		buf := &strings.Builder{}
-		for _, stmt := range appliedFile.Applied {
-			fmt.Fprintln(buf, stmt)
-		}
-		diags.AddWarning("Atlas Plan",
-			fmt.Sprintf("The following SQL statements will be executed:\n\n\n%s", buf.String()),
-		)
+	if appliedFile != nil {
+		stmts = appliedFile.Applied
 	}
-	return diags
+	return stmts, diags
 }
 
 func (r *AtlasSchemaResource) readSchema(ctx context.Context, data *AtlasSchemaResourceModel) (diags diag.Diagnostics) {
+	src, diags := r.inspectSchema(ctx, data, data.StrictMode.ValueBool())
+	if diags.HasError() {
+		return
+	}
+	// The inspected schema is parsed once for all computed attributes.
+	f, diags := parseInspected(src)
+	if diags.HasError() {
+		return
+	}
+	objs := fileObjects(f)
+	// Set the HCL value
+	data.HCL = types.StringValue(src)
+	data.ManagedObjects, diags = managedObjects(ctx, objs)
+	if diags.HasError() {
+		return
+	}
+	data.ManagedSchemas, diags = managedNames(ctx, objs, "schema")
+	if diags.HasError() {
+		return
+	}
+	data.ManagedViews, diags = managedNames(ctx, objs, "view")
+	if diags.HasError() {
+		return
+	}
+	data.SchemaObjectCounts, diags = objectCounts(ctx, f)
+	if diags.HasError() {
+		return
+	}
+	data.ManagedFunctions, diags = managedRoutines(ctx, f, "function")
+	if diags.HasError() {
+		return
+	}
+	data.ManagedProcedures, diags = managedRoutines(ctx, f, "procedure")
+	if diags.HasError() {
+		return
+	}
+	data.EffectiveExclude = types.ListValueMust(types.StringType, []attr.Value{})
+	if len(data.Exclude.Elements())+len(data.SkipTables.Elements()) > 0 {
+		// Inspect the database without the exclusions,
+		// to find the objects that were filtered out.
+		all := data.Clone()
+		all.Exclude = types.ListNull(types.StringType)
+		all.SkipTables = types.ListNull(types.StringType)
+		if src, diags = r.inspectSchema(ctx, all, false); diags.HasError() {
+			return
+		}
+		var af *hclwrite.File
+		if af, diags = parseInspected(src); diags.HasError() {
+			return
+		}
+		data.EffectiveExclude, diags = excludedObjects(ctx, fileObjects(af), objs)
+	}
+	return
+}
+
+// inspectSchema returns the inspected schema of the database. If strict is
+// true, an error is returned if the database has drifted from the HCL.
+func (r *AtlasSchemaResource) inspectSchema(ctx context.Context, data *AtlasSchemaResourceModel, strict bool) (_ string, diags diag.Diagnostics) {
 	cfg, wd, err := data.Workspace(ctx, &r.ProviderData)
 	if err != nil {
 		diags.AddError("Generate config failure",
@@ -392,8 +1180,33 @@ func (r *AtlasSchemaResource) readSchema(ctx context.Context, data *AtlasSchemaR
 		)
 		return
 	}
-	hcl, err := c.SchemaInspect(ctx, &atlas.SchemaInspectParams{
-		Env: cfg.EnvName,
+	if strict {
+		result, err := c.SchemaApply(ctx, &atlas.SchemaApplyParams{
+			Env:    cfg.EnvName,
+			TxMode: data.TxMode.ValueString(),
+			DryRun: true,
+		})
+		if err != nil {
+			diags.AddError("Atlas Plan Error",
+				fmt.Sprintf("Unable to generate migration plan, got error: %s", err),
+			)
+			return
+		}
+		if f := result.Applied; f != nil && len(f.Applied) > 0 {
+			diags.AddError("Schema Drift",
+				fmt.Sprintf("The database schema was changed outside of Terraform, and strict_mode is enabled. "+
+					"The following SQL statements are needed to restore it:\n\n\n%s", strings.Join(f.Applied, "\n")),
+			)
+			return
+		}
+	}
+	var schemas []string
+	if diags = data.IntrospectSchemas.ElementsAs(ctx, &schemas, false); diags.HasError() {
+		return
+	}
+	src, err := c.SchemaInspect(ctx, &atlas.SchemaInspectParams{
+		Env:    cfg.EnvName,
+		Schema: schemas,
 	})
 	if err != nil {
 		diags.AddError("Inspect Error",
@@ -401,9 +1214,27 @@ func (r *AtlasSchemaResource) readSchema(ctx context.Context, data *AtlasSchemaR
 		)
 		return
 	}
-	// Set the HCL value
-	data.HCL = types.StringValue(hcl)
-	return
+	return src, diags
+}
+
+// readObjects inspects the database and sets the managed_objects,
+// managed_schemas, managed_views, managed_functions, managed_procedures,
+// schema_object_counts and effective_exclude attributes, without changing
+// the HCL of the resource.
+func (r *AtlasSchemaResource) readObjects(ctx context.Context, data *AtlasSchemaResourceModel) diag.Diagnostics {
+	m := data.Clone()
+	m.StrictMode = types.BoolNull()
+	if diags := r.readSchema(ctx, m); diags.HasError() {
+		return diags
+	}
+	data.ManagedObjects = m.ManagedObjects
+	data.ManagedSchemas = m.ManagedSchemas
+	data.ManagedViews = m.ManagedViews
+	data.SchemaObjectCounts = m.SchemaObjectCounts
+	data.ManagedFunctions = m.ManagedFunctions
+	data.ManagedProcedures = m.ManagedProcedures
+	data.EffectiveExclude = m.EffectiveExclude
+	return nil
 }
 
 func (r *AtlasSchemaResource) applySchema(ctx context.Context, data *AtlasSchemaResourceModel) (diags diag.Diagnostics) {
@@ -427,17 +1258,59 @@ func (r *AtlasSchemaResource) applySchema(ctx context.Context, data *AtlasSchema
 		)
 		return
 	}
-	_, err = c.SchemaApply(ctx, &atlas.SchemaApplyParams{
-		Env:         cfg.EnvName,
-		TxMode:      data.TxMode.ValueString(),
-		AutoApprove: true,
-	})
+	if diags.Append(data.runScript(ctx, "pre_apply_sql", data.PreApplySQL)...); diags.HasError() {
+		return
+	}
+	apply := func() error {
+		_, err := c.SchemaApply(ctx, &atlas.SchemaApplyParams{
+			Env:         cfg.EnvName,
+			TxMode:      data.TxMode.ValueString(),
+			AutoApprove: true,
+		})
+		return err
+	}
+	if data.RetryOnLock.ValueBool() {
+		// Durations are validated in ValidateConfig.
+		timeout, _ := time.ParseDuration(defaultString(data.TimeoutOnLock, "1m"))
+		interval, _ := time.ParseDuration(defaultString(data.LockRetryInterval, "5s"))
+		err = data.Retry.do(ctx, func() error {
+			return retryOnLock(ctx, timeout, interval, apply)
+		})
+	} else {
+		err = data.Retry.do(ctx, apply)
+	}
 	if err != nil {
+		skip, sdiags := data.skipError(ctx, err)
+		if diags.Append(sdiags...); skip {
+			diags.AddWarning("Apply Error",
+				fmt.Sprintf("Ignoring error matched by skip_error_patterns: %s", err),
+			)
+			return diags
+		}
 		diags.AddError("Apply Error",
 			fmt.Sprintf("Unable to apply changes, got error: %s", err),
 		)
 		return
 	}
+	return append(diags, data.runScript(ctx, "post_apply_sql", data.PostApplySQL)...)
+}
+
+// runScript executes the script set by the given attribute, if any, on the database.
+func (d *AtlasSchemaResourceModel) runScript(ctx context.Context, name string, v types.String) (diags diag.Diagnostics) {
+	if v.IsNull() {
+		return nil
+	}
+	script, err := hookScript(v.ValueString())
+	if err == nil {
+		var u string
+		if u, err = absoluteSqliteURL(d.URL.ValueString()); err == nil {
+			err = execScript(ctx, u, script)
+		}
+	}
+	if err != nil {
+		diags.AddAttributeError(path.Root(name), "Apply script error",
+			fmt.Sprintf("Failed to execute %s: %s", name, err.Error()))
+	}
 	return diags
 }
 
@@ -479,7 +1352,14 @@ func (r *AtlasSchemaResource) firstRunCheck(ctx context.Context, data *AtlasSche
 			causes = append(causes, c)
 		}
 	}
-	if len(causes) > 0 {
+	switch {
+	case len(causes) > 0 && data.AutoBaseline.ValueBool():
+		diags.AddWarning(
+			"Existing schema resources",
+			fmt.Sprintf("The database contains resources that are not defined in the HCL file. "+
+				"They are kept on the first run because auto_baseline is enabled.\n\t- %s", strings.Join(causes, "\n\t- ")),
+		)
+	case len(causes) > 0:
 		diags.AddError(
 			"Unrecognized schema resources",
 			fmt.Sprintf(`The database contains resources that Atlas wants to drop because they are not defined in the HCL file on the first run.
@@ -495,6 +1375,14 @@ func (d *AtlasSchemaResourceModel) Workspace(ctx context.Context, p *ProviderDat
 	if err != nil {
 		return nil, nil, err
 	}
+	diff := d.Diff
+	if f := d.DiffPolicyFile; !f.IsNull() {
+		policy, err := diffPolicy(f.ValueString())
+		if err != nil {
+			return nil, nil, err
+		}
+		diff = mergeDiff(d.Diff, policy)
+	}
 	cfg := &projectConfig{
 		Cloud:   cloudConfig(p.Cloud),
 		EnvName: "tf",
@@ -502,9 +1390,27 @@ func (d *AtlasSchemaResourceModel) Workspace(ctx context.Context, p *ProviderDat
 			URL:    dbURL,
 			DevURL: defaultString(d.DevURL, p.DevURL),
 			Source: "file://schema.hcl",
-			Diff:   d.Diff,
+			Diff:   diff,
+			Lint:   mergeLint(d.Lint, p.Lint),
 		},
 	}
+	if f := d.ConfigFile; !f.IsNull() && !f.IsUnknown() {
+		b, err := os.ReadFile(f.ValueString())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read config_file: %w", err)
+		}
+		cfg.Config = string(b)
+	}
+	if cfg.Config == "" {
+		// Fall back to the atlas.hcl used by the Atlas CLI.
+		if cfg.Config, err = defaultConfig(cfg.EnvName); err != nil {
+			return nil, nil, err
+		}
+	}
+	if d.SkipNormalize.ValueBool() {
+		// Without a dev database, the HCL is not normalized.
+		cfg.Env.DevURL = ""
+	}
 	if cloud := p.Cloud; cloud.Valid() {
 		cfg.Cloud = &CloudConfig{
 			Token: cloud.Token.ValueString(),
@@ -514,10 +1420,41 @@ func (d *AtlasSchemaResourceModel) Workspace(ctx context.Context, p *ProviderDat
 	if diags.HasError() {
 		return nil, nil, errors.New(diags.Errors()[0].Summary())
 	}
+	if diags = d.Include.ElementsAs(ctx, &cfg.Env.Include, false); diags.HasError() {
+		return nil, nil, errors.New(diags.Errors()[0].Summary())
+	}
+	var skip []string
+	if diags = d.SkipTables.ElementsAs(ctx, &skip, false); diags.HasError() {
+		return nil, nil, errors.New(diags.Errors()[0].Summary())
+	}
+	for _, name := range skip {
+		cfg.Env.Exclude = append(cfg.Env.Exclude, skipTablePattern(name))
+	}
+	if isKnownList(d.BaselineExclude) {
+		var kept []string
+		if diags = d.BaselineExclude.ElementsAs(ctx, &kept, false); diags.HasError() {
+			return nil, nil, errors.New(diags.Errors()[0].Summary())
+		}
+		cfg.Env.Exclude = append(cfg.Env.Exclude, kept...)
+	}
+	src := d.HCL.ValueString()
+	if d.HCL.IsNull() || d.HCL.IsUnknown() {
+		// The HCL was not resolved from its source during
+		// the plan, e.g. when validating the configuration.
+		switch g, f := d.HCLFromGit, d.SchemaFilePath; {
+		case g != nil:
+			src, _, err = g.Fetch(ctx)
+		case !f.IsNull() && !f.IsUnknown():
+			src, err = readSchemaFile(f.ValueString())
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 	wd, err := atlas.NewWorkingDir(
 		atlas.WithAtlasHCL(cfg.Render),
 		func(ce *atlas.WorkingDir) error {
-			_, err = ce.WriteFile("schema.hcl", []byte(d.HCL.ValueString()))
+			_, err = ce.WriteFile("schema.hcl", []byte(src))
 			return err
 		},
 	)
@@ -527,6 +1464,551 @@ func (d *AtlasSchemaResourceModel) Workspace(ctx context.Context, p *ProviderDat
 	return cfg, wd, nil
 }
 
+// Fetch reads the file from the Git repository at the given ref,
+// and returns its content along with the SHA of the resolved commit.
+func (g *GitSource) Fetch(ctx context.Context) (src, sha string, err error) {
+	dir, err := os.MkdirTemp("", "atlas-git-")
+	if err != nil {
+		return "", "", err
+	}
+	defer os.RemoveAll(dir)
+	git := func(args ...string) (string, error) {
+		stderr := &bytes.Buffer{}
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+		cmd.Stderr = stderr
+		out, err := cmd.Output()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("git %s: %s", args[0], msg)
+			}
+			return "", fmt.Errorf("git %s: %w", args[0], err)
+		}
+		return string(out), nil
+	}
+	ref := defaultString(g.Ref, "HEAD")
+	if _, err = git("init", "-q"); err != nil {
+		return "", "", err
+	}
+	if _, err = git("fetch", "-q", "--depth", "1", "--", g.Repo.ValueString(), ref); err != nil {
+		return "", "", err
+	}
+	if sha, err = git("rev-parse", "FETCH_HEAD"); err != nil {
+		return "", "", err
+	}
+	if src, err = git("show", "FETCH_HEAD:"+strings.TrimPrefix(g.Path.ValueString(), "/")); err != nil {
+		return "", "", err
+	}
+	return src, strings.TrimSpace(sha), nil
+}
+
+// skipTablePattern returns the exclude pattern matching exactly the given
+// table name, in the form of "<table>" or "<schema>.<table>".
+func skipTablePattern(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = escapeGlob(p)
+	}
+	return strings.Join(parts, ".") + "[type=table]"
+}
+
+// escapeGlob escapes the glob meta characters of the given name.
+func escapeGlob(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// validations caches the results of hcl_validation_script runs,
+// keyed by the script path and the hclID of the validated HCL.
+var validations sync.Map
+
+// validateHCL runs the given script with the HCL piped to its stdin.
+// An error is returned if the script exits with a non-zero code.
+func validateHCL(ctx context.Context, script, src string) error {
+	key := script + ":" + hclID([]byte(src))
+	if v, ok := validations.Load(key); ok {
+		err, _ := v.(error)
+		return err
+	}
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Stdin = strings.NewReader(src)
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = errors.New(msg)
+		}
+	default:
+		// Do not cache failures to start the script.
+		return fmt.Errorf("failed to run %q: %w", script, err)
+	}
+	validations.Store(key, err)
+	return err
+}
+
+// baseline returns a copy of the model that excludes the objects that exist
+// in the database but are not defined in its HCL. These objects are kept on
+// the first run, and are not managed by the resource afterwards.
+func (r *AtlasSchemaResource) baseline(ctx context.Context, data *AtlasSchemaResourceModel) (*AtlasSchemaResourceModel, diag.Diagnostics) {
+	current := data.Clone()
+	current.StrictMode = types.BoolNull()
+	current.BaselineExclude = types.ListNull(types.StringType)
+	if diags := r.readSchema(ctx, current); diags.HasError() {
+		return nil, diags
+	}
+	var diags diag.Diagnostics
+	u, err := absoluteSqliteURL(data.URL.ValueString())
+	if err != nil {
+		diags.AddError("Generate config failure",
+			fmt.Sprintf("Failed to parse the database url: %s", err.Error()))
+		return nil, diags
+	}
+	scoped, err := schemaScoped(u)
+	if err != nil {
+		diags.AddError("Generate config failure",
+			fmt.Sprintf("Failed to parse the database url: %s", err.Error()))
+		return nil, diags
+	}
+	kept, err := baselineExclude(data.HCL.ValueString(), current.HCL.ValueString(), scoped)
+	if err != nil {
+		diags.AddError("Generate config failure",
+			fmt.Sprintf("Failed to parse the schema: %s", err.Error()))
+		return nil, diags
+	}
+	m := data.Clone()
+	m.BaselineExclude, diags = types.ListValueFrom(ctx, types.StringType, kept)
+	return m, diags
+}
+
+// schemaScoped reports if the database URL is bound to a single schema,
+// following the URL formats of the Atlas drivers: the search_path parameter
+// for PostgreSQL, the main file for SQLite and the path for the others.
+func schemaScoped(u string) (bool, error) {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return false, err
+	}
+	scheme, path := strings.ToLower(pu.Scheme), strings.Trim(pu.Path, "/")
+	if scheme == "docker" {
+		// The image is the host, followed by its tag and the
+		// database, e.g. docker://mysql/8/dev or docker://postgres/15/dev.
+		_, path, _ = strings.Cut(path, "/")
+		scheme = strings.ToLower(pu.Host)
+	}
+	switch {
+	case scheme == "postgres", scheme == "postgresql":
+		return pu.Query().Get("search_path") != "", nil
+	case scheme == SchemaTypeSQLite, strings.HasPrefix(scheme, "libsql"):
+		return true, nil
+	default:
+		return path != "", nil
+	}
+}
+
+// baselineExclude returns the exclude patterns matching the objects of the
+// inspected HCL that are not defined in the desired one. If scoped is true,
+// the patterns are relative to the schema the database URL is bound to.
+func baselineExclude(desired, inspected string, scoped bool) ([]string, error) {
+	d, diags := hclwrite.ParseConfig([]byte(desired), "schema.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	i, diags := hclwrite.ParseConfig([]byte(inspected), "schema.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	defined := make(map[string]bool)
+	for _, blk := range d.Body().Blocks() {
+		defined[blockKey(blk)] = true
+	}
+	kept := []string{}
+	for _, blk := range i.Body().Blocks() {
+		labels := blk.Labels()
+		if len(labels) == 0 || defined[blockKey(blk)] {
+			continue
+		}
+		name := escapeGlob(labels[len(labels)-1])
+		switch s := blockSchema(blk); {
+		case s != "" && scoped:
+			kept = append(kept, name)
+		case s != "":
+			kept = append(kept, escapeGlob(s)+"."+name)
+		case blk.Type() == "schema" && !scoped:
+			kept = append(kept, name)
+		}
+	}
+	return kept, nil
+}
+
+// blockKey returns the key of a top-level block, composed of its
+// type, its labels and the schema it belongs to, if any.
+func blockKey(b *hclwrite.Block) string {
+	k := strings.Join(append([]string{b.Type()}, b.Labels()...), ".")
+	if s := blockSchema(b); s != "" {
+		k = "schema." + s + "/" + k
+	}
+	return k
+}
+
+// blockSchema returns the name of the schema referenced
+// by the schema attribute of the block, if any.
+func blockSchema(b *hclwrite.Block) string {
+	a := b.Body().GetAttribute("schema")
+	if a == nil {
+		return ""
+	}
+	ref := strings.TrimSpace(string(a.Expr().BuildTokens(nil).Bytes()))
+	return strings.TrimPrefix(ref, "schema.")
+}
+
+// hclObjects returns the names of the objects defined
+// in the given HCL, grouped by their type.
+func hclObjects(src string) (map[string][]string, error) {
+	f, diags := hclwrite.ParseConfig([]byte(src), "schema.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return fileObjects(f), nil
+}
+
+// parseInspected parses the inspected schema.
+func parseInspected(src string) (*hclwrite.File, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	f, parseDiags := hclwrite.ParseConfig([]byte(src), "schema.hcl", hcl.InitialPos)
+	if parseDiags.HasErrors() {
+		diags.AddError("Inspect Error",
+			fmt.Sprintf("Unable to parse the inspected schema, got error: %s", parseDiags),
+		)
+	}
+	return f, diags
+}
+
+// fileObjects returns the names of the objects defined
+// in the parsed HCL file, grouped by their type.
+func fileObjects(f *hclwrite.File) map[string][]string {
+	objs := make(map[string][]string)
+	for _, blk := range f.Body().Blocks() {
+		labels := blk.Labels()
+		if len(labels) == 0 {
+			continue
+		}
+		objs[blk.Type()] = append(objs[blk.Type()], labels[len(labels)-1])
+		for _, nested := range blk.Body().Blocks() {
+			if l := nested.Labels(); nested.Type() == "index" && len(l) > 0 {
+				objs["index"] = append(objs["index"], l[len(l)-1])
+			}
+		}
+	}
+	for _, names := range objs {
+		slices.Sort(names)
+	}
+	return objs
+}
+
+// managedObjects returns the names of the given objects,
+// grouped by their type. Schemas are not included.
+func managedObjects(ctx context.Context, objs map[string][]string) (types.Map, diag.Diagnostics) {
+	managed := maps.Clone(objs)
+	delete(managed, "schema")
+	return types.MapValueFrom(ctx, managedObjectsType.ElemType, managed)
+}
+
+// managedNames returns the names of the given objects of the given type.
+func managedNames(ctx context.Context, objs map[string][]string, typ string) (types.List, diag.Diagnostics) {
+	names := objs[typ]
+	if names == nil {
+		names = []string{}
+	}
+	return types.ListValueFrom(ctx, types.StringType, names)
+}
+
+// objectCounts returns the number of objects defined in the given HCL,
+// keyed by the plural form of their type. Indexes and foreign keys are
+// counted across all tables.
+func objectCounts(ctx context.Context, f *hclwrite.File) (types.Map, diag.Diagnostics) {
+	plural := func(typ string) string {
+		if typ == "index" {
+			return "indexes"
+		}
+		return typ + "s"
+	}
+	counts := map[string]int64{"tables": 0, "views": 0, "indexes": 0, "foreign_keys": 0}
+	for _, blk := range f.Body().Blocks() {
+		if len(blk.Labels()) == 0 {
+			continue
+		}
+		counts[plural(blk.Type())]++
+		for _, nested := range blk.Body().Blocks() {
+			if typ := nested.Type(); typ == "index" || typ == "foreign_key" {
+				counts[plural(typ)]++
+			}
+		}
+	}
+	return types.MapValueFrom(ctx, types.Int64Type, counts)
+}
+
+// managedRoutines returns the functions or procedures defined in the given HCL.
+// The signature is the routine name followed by the types of its arguments,
+// e.g. "add(int, int)".
+func managedRoutines(ctx context.Context, f *hclwrite.File, typ string) (types.List, diag.Diagnostics) {
+	exprString := func(a *hclwrite.Attribute) string {
+		if a == nil {
+			return ""
+		}
+		return strings.TrimSpace(string(a.Expr().BuildTokens(nil).Bytes()))
+	}
+	routines := []attr.Value{}
+	for _, blk := range f.Body().Blocks() {
+		labels := blk.Labels()
+		if blk.Type() != typ || len(labels) == 0 {
+			continue
+		}
+		name := labels[len(labels)-1]
+		var args []string
+		for _, arg := range blk.Body().Blocks() {
+			if arg.Type() == "arg" {
+				args = append(args, exprString(arg.Body().GetAttribute("type")))
+			}
+		}
+		routines = append(routines, types.ObjectValueMust(managedRoutineType.AttrTypes, map[string]attr.Value{
+			"name":      types.StringValue(name),
+			"schema":    types.StringValue(strings.TrimPrefix(exprString(blk.Body().GetAttribute("schema")), "schema.")),
+			"signature": types.StringValue(fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))),
+		}))
+	}
+	return types.ListValue(managedRoutineType, routines)
+}
+
+// excludedObjects returns the objects of all that are not
+// in the filtered ones, in the form of "<type>.<name>".
+func excludedObjects(ctx context.Context, all, filtered map[string][]string) (types.List, diag.Diagnostics) {
+	excluded := make([]string, 0)
+	for typ, names := range mapsSorted(all) {
+		for _, name := range names {
+			if !slices.Contains(filtered[typ], name) {
+				excluded = append(excluded, typ+"."+name)
+			}
+		}
+	}
+	return types.ListValueFrom(ctx, types.StringType, excluded)
+}
+
+// diffPolicy reads the diff policy from the HCL file at the given file:// URL.
+func diffPolicy(s string) (*Diff, error) {
+	u, err := url.Parse(filepath.ToSlash(s))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse diff_policy_file: %w", err)
+	}
+	name := filepath.Join(u.Host, u.Path)
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read diff_policy_file: %w", err)
+	}
+	f, diags := hclsyntax.ParseConfig(b, name, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	d := &Diff{}
+	for _, blk := range f.Body.(*hclsyntax.Body).Blocks {
+		if blk.Type != "diff" {
+			return nil, fmt.Errorf("%s: unexpected block %q, only the diff block is allowed", name, blk.Type)
+		}
+		for _, nb := range blk.Body.Blocks {
+			switch nb.Type {
+			case "concurrent_index":
+				d.ConcurrentIndex = &ConcurrentIndex{}
+				err = decodeBools(nb.Body, d.ConcurrentIndex)
+			case "skip":
+				d.Skip = &SkipChanges{}
+				err = decodeBools(nb.Body, d.Skip)
+			default:
+				err = fmt.Errorf("unexpected block %q", nb.Type)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return d, nil
+}
+
+// decodeBools decodes the boolean attributes of the body
+// into the types.Bool fields of dst, matched by their tfsdk tag.
+func decodeBools(body *hclsyntax.Body, dst any) error {
+	rv := reflect.ValueOf(dst).Elem()
+	fields := make(map[string]int, rv.NumField())
+	for i := 0; i < rv.NumField(); i++ {
+		fields[rv.Type().Field(i).Tag.Get("tfsdk")] = i
+	}
+	for name, attr := range body.Attributes {
+		i, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("unexpected attribute %q", name)
+		}
+		v, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return diags
+		}
+		if v.IsNull() || !v.Type().Equals(cty.Bool) {
+			return fmt.Errorf("attribute %q must be a bool", name)
+		}
+		rv.Field(i).Set(reflect.ValueOf(types.BoolValue(v.True())))
+	}
+	return nil
+}
+
+// mergeDiff merges the inline diff policy with the one read from a file.
+// Values set in the inline policy take precedence.
+func mergeDiff(inline, file *Diff) *Diff {
+	switch {
+	case inline == nil:
+		return file
+	case file == nil:
+		return inline
+	}
+	return &Diff{
+		ConcurrentIndex: mergeBools(inline.ConcurrentIndex, file.ConcurrentIndex),
+		Skip:            mergeBools(inline.Skip, file.Skip),
+	}
+}
+
+// mergeBools returns a copy of base, with the non-null
+// types.Bool fields of inline set on it.
+func mergeBools[T any](inline, base *T) *T {
+	switch {
+	case inline == nil:
+		return base
+	case base == nil:
+		return inline
+	}
+	m := *base
+	iv, mv := reflect.ValueOf(inline).Elem(), reflect.ValueOf(&m).Elem()
+	for i := 0; i < iv.NumField(); i++ {
+		if b, ok := iv.Field(i).Interface().(types.Bool); ok && !b.IsNull() {
+			mv.Field(i).Set(iv.Field(i))
+		}
+	}
+	return &m
+}
+
+// lockError matches errors caused by lock wait timeouts.
+var lockError = regexp.MustCompile(`(?i)lock wait timeout|lock timeout|database is locked|could not obtain lock`)
+
+// retryOnLock calls fn until it succeeds, fails with an error that is not
+// a lock wait timeout, or the timeout (or the context deadline) is reached.
+func retryOnLock(ctx context.Context, timeout, interval time.Duration, fn func() error) error {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	for {
+		err := fn()
+		if err == nil || !lockError.MatchString(err.Error()) || time.Now().Add(interval).After(deadline) {
+			return err
+		}
+		tflog.Debug(ctx, "Retrying on lock wait timeout", map[string]any{
+			"error": err,
+		})
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(interval):
+		}
+	}
+}
+
+// validate validates the retry policy.
+func (r *Retry) validate() (diags diag.Diagnostics) {
+	if r == nil {
+		return nil
+	}
+	if v := r.Interval; !v.IsNull() && !v.IsUnknown() {
+		if d, err := time.ParseDuration(v.ValueString()); err != nil || d <= 0 {
+			diags.AddAttributeError(
+				path.Root("retry").AtName("interval"),
+				"Invalid interval",
+				fmt.Sprintf("The value %q is not a valid positive duration", v.ValueString()),
+			)
+		}
+	}
+	if v := r.RetryOn; !v.IsNull() && !v.IsUnknown() {
+		if _, err := regexp.Compile(v.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("retry").AtName("retry_on"),
+				"Invalid retry_on",
+				fmt.Sprintf("The pattern %q is not a valid regular expression: %s", v.ValueString(), err),
+			)
+		}
+	}
+	return diags
+}
+
+// do calls fn until it succeeds, fails with an error that does
+// not match retry_on, or the attempts are exhausted. If the context
+// deadline does not leave room for another attempt, the last error
+// is returned. A nil policy calls fn once.
+func (r *Retry) do(ctx context.Context, fn func() error) error {
+	if r == nil {
+		return fn()
+	}
+	attempts := int64(3)
+	if !r.MaxAttempts.IsNull() {
+		attempts = r.MaxAttempts.ValueInt64()
+	}
+	// Values are validated in ValidateConfig.
+	interval, _ := time.ParseDuration(defaultString(r.Interval, "5s"))
+	var retryOn *regexp.Regexp
+	if v := r.RetryOn.ValueString(); v != "" {
+		retryOn = regexp.MustCompile(v)
+	}
+	for i := int64(1); ; i++ {
+		err := fn()
+		if err == nil || i >= attempts || retryOn != nil && !retryOn.MatchString(err.Error()) {
+			return err
+		}
+		if d, ok := ctx.Deadline(); ok && time.Now().Add(interval).After(d) {
+			return err
+		}
+		tflog.Debug(ctx, "Retrying after a failed attempt", map[string]any{
+			"attempt": i,
+			"error":   err,
+		})
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(interval):
+		}
+	}
+}
+
+// skipError reports whether the given error matches one of the skip_error_patterns.
+func (d *AtlasSchemaResourceModel) skipError(ctx context.Context, err error) (bool, diag.Diagnostics) {
+	var patterns []string
+	if diags := d.SkipErrorPatterns.ElementsAs(ctx, &patterns, false); diags.HasError() {
+		return false, diags
+	}
+	for _, p := range patterns {
+		re, rerr := regexp.Compile(p)
+		if rerr != nil {
+			// Patterns are validated in ValidateConfig.
+			continue
+		}
+		if re.MatchString(err.Error()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func urlToID(u types.String) string {
 	uu, err := url.Parse(u.ValueString())
 	if err != nil {
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	atlas "ariga.io/atlas-go-sdk/atlasexec"
+)
+
+type (
+	// AtlasSchemaStateDataSource defines the data source implementation.
+	AtlasSchemaStateDataSource struct {
+		ProviderData
+	}
+	// AtlasSchemaStateDataSourceModel describes the data source data model.
+	AtlasSchemaStateDataSourceModel struct {
+		URL     types.String `tfsdk:"url"`
+		Schemas types.List   `tfsdk:"schemas"`
+		Exclude types.List   `tfsdk:"exclude"`
+		HCL     types.String `tfsdk:"hcl"`
+		ID      types.String `tfsdk:"id"`
+	}
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource              = &AtlasSchemaStateDataSource{}
+	_ datasource.DataSourceWithConfigure = &AtlasSchemaStateDataSource{}
+)
+
+// NewAtlasSchemaStateDataSource returns a new AtlasSchemaStateDataSource.
+func NewAtlasSchemaStateDataSource() datasource.DataSource {
+	return &AtlasSchemaStateDataSource{}
+}
+
+// Metadata implements datasource.DataSource.
+func (d *AtlasSchemaStateDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema_state"
+}
+
+// Configure implements datasource.DataSourceWithConfigure.
+func (d *AtlasSchemaStateDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	resp.Diagnostics.Append(d.configure(req.ProviderData)...)
+}
+
+// Schema implements datasource.DataSource.
+func (d *AtlasSchemaStateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data source inspects the current schema of a database, without a dev-db. " +
+			"The result is not normalized, use the atlas_schema data source to compute the HCL used in diffs. " +
+			"Reading it emits a warning to that effect.",
+		Attributes: map[string]schema.Attribute{
+			"url": schema.StringAttribute{
+				Description: "The url of the database see https://atlasgo.io/cli/url",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"schemas": schema.ListAttribute{
+				Description: "The schemas to inspect. By default, all schemas the url has access to are inspected",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"exclude": schema.ListAttribute{
+				Description: "Filter out resources matching the given glob pattern. See https://atlasgo.io/declarative/inspect#exclude-schemas",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"hcl": schema.StringAttribute{
+				Description: "The inspected schema in HCL. It is not normalized, " +
+					"and should not be compared to the hcl of an atlas_schema resource",
+				Computed: true,
+			},
+			"id": schema.StringAttribute{
+				Description: "The ID of this data source",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read implements datasource.DataSource.
+func (d *AtlasSchemaStateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AtlasSchemaStateDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	cfg, wd, err := data.Workspace(ctx, &d.ProviderData)
+	if err != nil {
+		resp.Diagnostics.AddError("Generate config failure",
+			fmt.Sprintf("Failed to create workspace: %s", err.Error()))
+		return
+	}
+	defer func() {
+		if err := wd.Close(); err != nil {
+			tflog.Debug(ctx, "Failed to cleanup working directory", map[string]any{
+				"error": err,
+			})
+		}
+	}()
+	c, err := d.Client(wd.Path(), cfg.Cloud)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error",
+			fmt.Sprintf("Unable to create client, got error: %s", err),
+		)
+		return
+	}
+	hcl, err := c.SchemaInspect(ctx, &atlas.SchemaInspectParams{
+		Env: cfg.EnvName,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Inspect Error",
+			fmt.Sprintf("Unable to inspect the database, got error: %s", err),
+		)
+		return
+	}
+	data.HCL = types.StringValue(hcl)
+	data.ID = types.StringValue(hclID([]byte(hcl)))
+	resp.Diagnostics.AddWarning("Unnormalized schema",
+		"The hcl of atlas_schema_state is inspected without a dev-db and is not normalized, "+
+			"it should not be used to compute schema diffs. Use the atlas_schema data source for a normalized hcl.",
+	)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *AtlasSchemaStateDataSourceModel) Workspace(ctx context.Context, p *ProviderData) (*projectConfig, *atlas.WorkingDir, error) {
+	dbURL, err := absoluteSqliteURL(d.URL.ValueString())
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg := &projectConfig{
+		Cloud:   cloudConfig(p.Cloud),
+		EnvName: "tf",
+		// The dev-db is omitted on purpose, as
+		// the inspected schema is not normalized.
+		Env: &envConfig{
+			URL: dbURL,
+		},
+	}
+	if diags := d.Schemas.ElementsAs(ctx, &cfg.Env.Schemas, false); diags.HasError() {
+		return nil, nil, errors.New(diags.Errors()[0].Summary())
+	}
+	if diags := d.Exclude.ElementsAs(ctx, &cfg.Env.Exclude, false); diags.HasError() {
+		return nil, nil, errors.New(diags.Errors()[0].Summary())
+	}
+	wd, err := atlas.NewWorkingDir(atlas.WithAtlasHCL(cfg.Render))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	return cfg, wd, nil
+}
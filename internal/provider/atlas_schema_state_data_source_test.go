@@ -0,0 +1,40 @@
+package provider_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSchemaStateDataSource(t *testing.T) {
+	c := tempSchemas(t, mysqlURL, "test_schema_state")
+	createTables(t, c,
+		"CREATE TABLE `test_schema_state`.`users` (`id` int NOT NULL)",
+		"CREATE TABLE `test_schema_state`.`legacy` (`id` int NOT NULL)",
+	)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`data "atlas_schema_state" "current" {
+					url     = "%s"
+					schemas = ["test_schema_state"]
+					exclude = ["test_schema_state.legacy"]
+				}`, mysqlURL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("data.atlas_schema_state.current", "hcl", regexp.MustCompile(`table "users" {`)),
+					resource.TestCheckResourceAttrWith("data.atlas_schema_state.current", "hcl", func(v string) error {
+						if regexp.MustCompile(`table "legacy"`).MatchString(v) {
+							return fmt.Errorf("excluded table legacy was inspected")
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrSet("data.atlas_schema_state.current", "id"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type (
+	// AtlasVersionDataSource defines the data source implementation.
+	AtlasVersionDataSource struct {
+		ProviderData
+	}
+	// AtlasVersionDataSourceModel describes the data source data model.
+	AtlasVersionDataSourceModel struct {
+		Version types.String `tfsdk:"version"`
+		SHA     types.String `tfsdk:"sha"`
+		Canary  types.Bool   `tfsdk:"canary"`
+		ID      types.String `tfsdk:"id"`
+	}
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource              = &AtlasVersionDataSource{}
+	_ datasource.DataSourceWithConfigure = &AtlasVersionDataSource{}
+)
+
+// NewAtlasVersionDataSource returns a new AtlasVersionDataSource.
+func NewAtlasVersionDataSource() datasource.DataSource {
+	return &AtlasVersionDataSource{}
+}
+
+// Metadata implements datasource.DataSource.
+func (d *AtlasVersionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_version"
+}
+
+// Configure implements datasource.DataSourceWithConfigure.
+func (d *AtlasVersionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	resp.Diagnostics.Append(d.configure(req.ProviderData)...)
+}
+
+// Schema implements datasource.DataSource.
+func (d *AtlasVersionDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data source returns the version of the Atlas CLI used by the provider.",
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{
+				Description: "The version of the Atlas CLI, e.g. 0.27.0",
+				Computed:    true,
+			},
+			"sha": schema.StringAttribute{
+				Description: "The commit SHA the Atlas CLI was built from",
+				Computed:    true,
+			},
+			"canary": schema.BoolAttribute{
+				Description: "Whether the Atlas CLI is a canary build",
+				Computed:    true,
+			},
+			"id": schema.StringAttribute{
+				Description: "The full version of the Atlas CLI, in the format of <version>-<sha>[-canary]",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read implements datasource.DataSource.
+func (d *AtlasVersionDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	v := d.AtlasVersion
+	if v == nil {
+		resp.Diagnostics.AddError("Unknown atlas version",
+			"The version of the Atlas CLI is not available, the provider may not be configured")
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &AtlasVersionDataSourceModel{
+		Version: types.StringValue(v.Version),
+		SHA:     types.StringValue(v.SHA),
+		Canary:  types.BoolValue(v.Canary),
+		ID:      types.StringValue(versionString(v)),
+	})...)
+}
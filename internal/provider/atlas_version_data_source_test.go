@@ -0,0 +1,26 @@
+package provider_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccVersionDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "atlas_version" "current" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("data.atlas_version.current", "version", regexp.MustCompile(`^\d+\.\d+\.\d+`)),
+					resource.TestCheckResourceAttrSet("data.atlas_version.current", "sha"),
+					resource.TestCheckResourceAttrSet("data.atlas_version.current", "canary"),
+					resource.TestMatchResourceAttr("data.atlas_version.current", "id", regexp.MustCompile(`^\d+\.\d+\.\d+.*-.+`)),
+				),
+			},
+		},
+	})
+}
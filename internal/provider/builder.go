@@ -8,6 +8,7 @@ import (
 	"iter"
 	"maps"
 	"net/url"
+	"os"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -39,7 +40,9 @@ type (
 		Source    string
 		Schemas   []string
 		Exclude   []string
+		Include   []string
 		Diff      *Diff
+		Lint      *Lint
 		Migration *migrationConfig
 	}
 	CloudConfig struct {
@@ -88,6 +91,9 @@ func (env *envConfig) AsBlock() *hclwrite.Block {
 	if l := deleteZero(env.Exclude); len(l) > 0 {
 		e.SetAttributeValue("exclude", listStringVal(l))
 	}
+	if l := deleteZero(env.Include); len(l) > 0 {
+		e.SetAttributeValue("include", listStringVal(l))
+	}
 	if md := env.Migration; md != nil {
 		m := e.AppendNewBlock("migration", nil).Body()
 		if md.DirURL != "" {
@@ -129,8 +135,20 @@ func (env *envConfig) AsBlock() *hclwrite.Block {
 			attrBoolPtr(b, v.AddForeignKey, "add_foreign_key")
 			attrBoolPtr(b, v.DropForeignKey, "drop_foreign_key")
 			attrBoolPtr(b, v.ModifyForeignKey, "modify_foreign_key")
+			attrBoolPtr(b, v.AddCheck, "add_check")
+			attrBoolPtr(b, v.DropCheck, "drop_check")
+			attrBoolPtr(b, v.AddView, "add_view")
+			attrBoolPtr(b, v.DropView, "drop_view")
+			attrBoolPtr(b, v.ModifyView, "modify_view")
+			attrBoolPtr(b, v.AddSequence, "add_sequence")
+			attrBoolPtr(b, v.DropSequence, "drop_sequence")
+			attrBoolPtr(b, v.ModifySequence, "modify_sequence")
 		}
 	}
+	if l := env.Lint; l != nil && l.Review.ValueString() != "" {
+		b := e.AppendNewBlock("lint", nil).Body()
+		b.SetAttributeValue("review", cty.StringVal(l.Review.ValueString()))
+	}
 	return blk
 }
 
@@ -233,54 +251,62 @@ func mergeEnvBlock(dst *hclwrite.Body, blk *hclwrite.Block, name string) error {
 	}
 }
 
+// searchBlock returns the block of the given type to merge with. The block
+// labeled with name is preferred, then the unnamed block. An error is returned
+// if blocks of this type exist but none matches, or if the match is ambiguous.
 func searchBlock(parent *hclwrite.Body, typ, name string) (*hclwrite.Block, error) {
-	blocks := parent.Blocks()
-	typBlocks := make([]*hclwrite.Block, 0, len(blocks))
-	for _, b := range blocks {
-		if b.Type() == typ {
-			typBlocks = append(typBlocks, b)
+	var (
+		found          bool
+		named, unnamed []*hclwrite.Block
+	)
+	for _, b := range parent.Blocks() {
+		if b.Type() != typ {
+			continue
+		}
+		found = true
+		switch labels := b.Labels(); {
+		case len(labels) == 0:
+			unnamed = append(unnamed, b)
+		case len(labels) == 1 && labels[0] == name:
+			named = append(named, b)
 		}
 	}
-	if len(typBlocks) == 0 {
+	switch {
+	case len(named) > 1:
+		return nil, fmt.Errorf(`multiple %s blocks named %q were found in the given config`, typ, name)
+	case len(named) == 1:
+		return named[0], nil
+	case len(unnamed) > 1:
+		return nil, fmt.Errorf(`multiple unnamed %s blocks were found in the given config`, typ)
+	case len(unnamed) == 1:
+		return unnamed[0], nil
+	case found:
+		// Has blocks but none matched.
+		return nil, fmt.Errorf(`the %s block %q was not found in the give config`, typ, name)
+	default:
 		// No things here, return nil.
 		return nil, nil
 	}
-	// Check if there is a block with the given name.
-	idx := slices.IndexFunc(typBlocks, func(b *hclwrite.Block) bool {
-		labels := b.Labels()
-		return len(labels) == 1 && labels[0] == name
-	})
-	if idx == -1 {
-		// No block matched, check if there is an unnamed env block.
-		idx = slices.IndexFunc(typBlocks, func(b *hclwrite.Block) bool {
-			return len(b.Labels()) == 0
-		})
-		if idx == -1 {
-			// Has blocks but none matched.
-			return nil, fmt.Errorf(`the %s block %q was not found in the give config`, typ, name)
-		}
-	}
-	return typBlocks[idx], nil
 }
 
+// mergeBlock merges the attributes and the nested blocks of src into dst.
+// Attributes of src override the ones of dst. Nested blocks are matched by
+// their type and labels, matched pairs are merged recursively, and blocks
+// that exist only in src are appended to dst.
 func mergeBlock(dst, src *hclwrite.Block) {
 	dstBody, srcBody := dst.Body(), src.Body()
 	for name, attr := range mapsSorted(srcBody.Attributes()) {
 		dstBody.SetAttributeRaw(name, attr.Expr().BuildTokens(nil))
 	}
-	srcBlocks := srcBody.Blocks()
-	srcBlockTypes := make(map[string]struct{})
-	for _, blk := range srcBlocks {
-		srcBlockTypes[blk.Type()] = struct{}{}
-	}
-	for _, blk := range dstBody.Blocks() {
-		if _, conflict := srcBlockTypes[blk.Type()]; conflict {
-			// Remove the block from the destination if it already exists.
-			dstBody.RemoveBlock(blk)
+	for _, blk := range srcBody.Blocks() {
+		idx := slices.IndexFunc(dstBody.Blocks(), func(b *hclwrite.Block) bool {
+			return b.Type() == blk.Type() && slices.Equal(b.Labels(), blk.Labels())
+		})
+		if idx == -1 {
+			appendBlock(dstBody, blk)
+			continue
 		}
-	}
-	for _, blk := range srcBlocks {
-		appendBlock(dstBody, blk)
+		mergeBlock(dstBody.Blocks()[idx], blk)
 	}
 }
 
@@ -330,6 +356,52 @@ func absoluteFileURL(s string) (string, error) {
 	}
 }
 
+// envAtlasConfig is the environment variable of the Atlas CLI
+// that points to the default atlas.hcl file.
+const envAtlasConfig = "ATLAS_CONFIG"
+
+// defaultConfig returns the content of the atlas.hcl file set by the ATLAS_CONFIG
+// environment variable, or an empty string if it is unset. The variable holds
+// either a path or a file:// URL. If the file has env blocks, but none that the
+// given env can be merged with, they are removed and the other blocks are kept.
+func defaultConfig(env string) (string, error) {
+	v := os.Getenv(envAtlasConfig)
+	if v == "" {
+		return "", nil
+	}
+	p := v
+	if u, err := url.Parse(filepath.ToSlash(v)); err == nil && u.Scheme == SchemaTypeFile {
+		p = filepath.Join(u.Host, u.Path)
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the %s file: %w", envAtlasConfig, err)
+	}
+	f, diags := hclwrite.ParseConfig(b, p, hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", fmt.Errorf("failed to parse the %s file: %w", envAtlasConfig, diags)
+	}
+	var envs []*hclwrite.Block
+	for _, blk := range f.Body().Blocks() {
+		if blk.Type() != "env" {
+			continue
+		}
+		if l := blk.Labels(); len(l) == 0 || (len(l) == 1 && l[0] == env) {
+			// The block is merged with the env.
+			return string(b), nil
+		}
+		envs = append(envs, blk)
+	}
+	if len(envs) == 0 {
+		return string(b), nil
+	}
+	// The file holds only the envs of other projects, e.g. env "dev".
+	for _, blk := range envs {
+		f.Body().RemoveBlock(blk)
+	}
+	return string(f.Bytes()), nil
+}
+
 // absoluteSqliteURL returns the absolute path of a sqlite URL.
 func absoluteSqliteURL(s string) (string, error) {
 	if s == "" {
@@ -346,10 +418,21 @@ func absoluteSqliteURL(s string) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		return (&url.URL{
-			Scheme:   u.Scheme,
-			Path:     path,
-			RawQuery: u.RawQuery,
-		}).String(), nil
+		return sqliteURL(filepath.ToSlash(path), u.RawQuery), nil
 	}
 }
+
+// sqliteURL returns the SQLite URL of the given absolute, slash-separated
+// path. Windows drive-letter paths (C:/...) are prefixed with a slash, to
+// not be parsed as the host of the URL. UNC paths (//server/share/...)
+// are kept as is, and are rendered with an empty host.
+func sqliteURL(path, query string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{
+		Scheme:   SchemaTypeSQLite,
+		Path:     path,
+		RawQuery: query,
+	}).String()
+}
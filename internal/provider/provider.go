@@ -5,17 +5,20 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/mitchellh/go-homedir"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"golang.org/x/mod/semver"
@@ -35,14 +38,24 @@ type (
 		BinaryPath types.String `tfsdk:"binary_path"`
 		// DevURL is the URL of the dev-db.
 		DevURL types.String `tfsdk:"dev_url"`
+		// Debug exposes the generated Atlas configuration in the state.
+		Debug types.Bool `tfsdk:"debug"`
+		// RequiredAtlasVersion is the version constraint of the atlas-cli.
+		RequiredAtlasVersion types.String `tfsdk:"required_atlas_version"`
 		// Cloud is the Atlas Cloud configuration.
 		Cloud *AtlasCloudBlock `tfsdk:"cloud"`
+		// Lint is the default lint policy of the resources.
+		Lint *Lint `tfsdk:"lint"`
 	}
 	AtlasCloudBlock struct {
 		Token   types.String `tfsdk:"token"`
 		URL     types.String `tfsdk:"url"`
 		Project types.String `tfsdk:"project"`
 	}
+	// Lint defines the lint policy of the changes.
+	Lint struct {
+		Review types.String `tfsdk:"review"`
+	}
 	AtlasExec interface {
 		MigrateApply(context.Context, *atlas.MigrateApplyParams) (*atlas.MigrateApply, error)
 		MigrateDown(context.Context, *atlas.MigrateDownParams) (*atlas.MigrateDown, error)
@@ -60,11 +73,18 @@ type (
 	ProviderData struct {
 		// DevURL is the URL of the dev-db.
 		DevURL string
+		// Debug exposes the generated Atlas configuration in the state.
+		Debug bool
 		// Cloud is the Atlas Cloud configuration.
 		Cloud *AtlasCloudBlock
+		// Lint is the default lint policy of the resources.
+		Lint *Lint
 		// Client is the factory function to create a new AtlasExec Client.
 		// It is set during the provider configuration.
 		Client func(wd string, c *CloudConfig) (AtlasExec, error)
+		// AtlasVersion is the version of the atlas-cli, checked
+		// during the provider configuration.
+		AtlasVersion *atlas.Version
 		// version is set to the provider version on release, "dev" when the
 		// provider is built and ran locally, and "test" when running acceptance
 		// testing.
@@ -86,6 +106,19 @@ var (
 			},
 		},
 	}
+	lintBlock = schema.SingleNestedBlock{
+		Description: "The lint policy of the changes",
+		Attributes: map[string]schema.Attribute{
+			"review": schema.StringAttribute{
+				Description: "When to review the changes. One of `ERROR` (when lint errors are found), " +
+					"`WARNING` (when lint warnings or errors are found) or `ALWAYS`",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ERROR", "WARNING", "ALWAYS"),
+				},
+			},
+		},
+	}
 )
 
 // Ensure AtlasProvider satisfies various provider interfaces.
@@ -118,9 +151,13 @@ func (p *AtlasProvider) Metadata(ctx context.Context, req provider.MetadataReque
 func (p *AtlasProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "The Atlas provider is used to manage your database migrations, using the DDL of Atlas.\n" +
-			"For documentation about Atlas, visit: https://atlasgo.io",
+			"For documentation about Atlas, visit: https://atlasgo.io\n\n" +
+			"Resources that set neither `config` nor `config_file` use the atlas.hcl file set by the " +
+			"`ATLAS_CONFIG` environment variable as their base configuration, if it is set. " +
+			"Env blocks of this file that do not match the env of the resource are ignored.",
 		Blocks: map[string]schema.Block{
 			"cloud": cloudBlock,
+			"lint":  lintBlock,
 		},
 		Attributes: map[string]schema.Attribute{
 			"binary_path": schema.StringAttribute{
@@ -132,6 +169,15 @@ func (p *AtlasProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"debug": schema.BoolAttribute{
+				Description: "Store the configuration generated for the Atlas CLI in the state of the resources, for debugging purposes.",
+				Optional:    true,
+			},
+			"required_atlas_version": schema.StringAttribute{
+				Description: "The version constraint of the atlas-cli, as a comma-separated list of comparisons, " +
+					"e.g. `>= v0.27.0, < v1.0.0`. A version without an operator must match exactly.",
+				Optional: true,
+			},
 		},
 	}
 }
@@ -163,6 +209,9 @@ func (p *AtlasProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return c, nil
 	}
 	var cloud *CloudConfig
+	if model != nil {
+		model.Cloud.envToken()
+	}
 	if model != nil && model.Cloud.Valid() {
 		cloud = &CloudConfig{
 			Token: model.Cloud.Token.ValueString(),
@@ -178,15 +227,24 @@ func (p *AtlasProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		resp.Diagnostics.AddError("Check atlas version failure", err.Error())
 		return
 	}
-	version := fmt.Sprintf("%s-%s", v.Version, v.SHA)
-	if v.Canary {
-		version += "-canary"
+	tflog.Debug(ctx, "found atlas-cli", map[string]any{"version": versionString(v)})
+	if model != nil && model.RequiredAtlasVersion.ValueString() != "" {
+		if err := checkVersion(model.RequiredAtlasVersion.ValueString(), v.Version); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				tfpath.Root("required_atlas_version"),
+				"Unsupported atlas-cli version",
+				fmt.Sprintf("%s.\n\nTo upgrade the atlas-cli, see: https://atlasgo.io/getting-started#installation", err),
+			)
+			return
+		}
 	}
-	tflog.Debug(ctx, "found atlas-cli", map[string]any{"version": version})
 	p.data.Client = fnClient
+	p.data.AtlasVersion = v
 	p.data.Cloud = model.Cloud
 	if model != nil {
 		p.data.DevURL = model.DevURL.ValueString()
+		p.data.Debug = model.Debug.ValueBool()
+		p.data.Lint = model.Lint
 	}
 	resp.DataSourceData = p.data
 	resp.ResourceData = p.data
@@ -197,6 +255,10 @@ func (p *AtlasProvider) DataSources(ctx context.Context) []func() datasource.Dat
 	return []func() datasource.DataSource{
 		NewAtlasSchemaDataSource,
 		NewMigrationDataSource,
+		NewAtlasVersionDataSource,
+		NewAtlasSchemaDiffDataSource,
+		NewMigrationFilesDataSource,
+		NewAtlasSchemaStateDataSource,
 	}
 }
 
@@ -210,6 +272,21 @@ func (p *AtlasProvider) Resources(ctx context.Context) []func() resource.Resourc
 
 // ConfigValidators returns a list of functions which will all be performed during validation.
 func (p *AtlasProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var model *AtlasProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if model != nil && model.Cloud != nil {
+		if t := model.Cloud.Token; !t.IsUnknown() && t.ValueString() == "" && os.Getenv("ATLAS_TOKEN") == "" {
+			resp.Diagnostics.AddAttributeWarning(
+				tfpath.Root("cloud").AtName("token"),
+				"Missing Atlas Cloud token",
+				"The cloud block is set, but no token is provided. "+
+					"Set the cloud.token attribute or the ATLAS_TOKEN environment variable",
+			)
+		}
+	}
 	v := p.data.Version
 	if v == "dev" || v == "test" {
 		return
@@ -309,6 +386,33 @@ func (c *AtlasCloudBlock) Valid() bool {
 	return c != nil && c.Token.ValueString() != ""
 }
 
+// envToken sets the token of the cloud block from the ATLAS_TOKEN
+// environment variable, if it is not set in the configuration.
+func (c *AtlasCloudBlock) envToken() {
+	if c == nil || c.Token.IsUnknown() || c.Token.ValueString() != "" {
+		return
+	}
+	if t := os.Getenv("ATLAS_TOKEN"); t != "" {
+		c.Token = types.StringValue(t)
+	}
+}
+
+// mergeLint returns the lint policy of the resource, with the
+// attributes that are not set taken from the provider policy.
+func mergeLint(resource, provider *Lint) *Lint {
+	switch {
+	case resource == nil:
+		return provider
+	case provider == nil:
+		return resource
+	}
+	m := *resource
+	if m.Review.IsNull() {
+		m.Review = provider.Review
+	}
+	return &m
+}
+
 func cloudConfig(c ...*AtlasCloudBlock) *CloudConfig {
 	for _, b := range c {
 		if b.Valid() {
@@ -318,6 +422,68 @@ func cloudConfig(c ...*AtlasCloudBlock) *CloudConfig {
 	return nil
 }
 
+// versionString returns the version of the atlas-cli in the
+// format of <version>-<sha>[-canary].
+func versionString(v *atlas.Version) string {
+	s := fmt.Sprintf("%s-%s", v.Version, v.SHA)
+	if v.Canary {
+		s += "-canary"
+	}
+	return s
+}
+
+// checkVersion returns an error if the version does not satisfy
+// the constraint. The constraint is a comma-separated list of
+// comparisons, e.g. ">= v0.27.0, < v1.0.0".
+func checkVersion(constraint, version string) error {
+	v := semverOf(version)
+	if !semver.IsValid(v) {
+		return fmt.Errorf("invalid atlas-cli version %q", version)
+	}
+	for _, c := range strings.Split(constraint, ",") {
+		c = strings.TrimSpace(c)
+		op := "="
+		for _, o := range []string{">=", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(c, o) {
+				op, c = o, strings.TrimSpace(c[len(o):])
+				break
+			}
+		}
+		want := semverOf(c)
+		if !semver.IsValid(want) {
+			return fmt.Errorf("invalid version constraint %q", constraint)
+		}
+		var ok bool
+		switch cmp := semver.Compare(v, want); op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		default:
+			ok = cmp == 0
+		}
+		if !ok {
+			return fmt.Errorf("the atlas-cli version %s does not satisfy the constraint %q", v, constraint)
+		}
+	}
+	return nil
+}
+
+// semverOf returns the version with the "v" prefix
+// expected by the semver package.
+func semverOf(v string) string {
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return v
+}
+
 // checkForUpdate checks for version updates and security advisories for Atlas.
 func checkForUpdate(_ context.Context, version string) (string, error) {
 	// Users may skip update checking behavior.
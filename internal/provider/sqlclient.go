@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/sqlite"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	_ "ariga.io/atlas/sql/mysql"
+	_ "ariga.io/atlas/sql/postgres"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// sqlClientSchemes are the URL schemes of the databases the provider
+// connects to directly, e.g. to run the SQL scripts of the resources.
+var sqlClientSchemes = []string{
+	"mysql", "mysql+unix", "maria", "maria+unix", "mariadb", "mariadb+unix",
+	"postgres", "postgresql",
+	SchemaTypeSQLite,
+}
+
+// checkSQLClientURL returns an error if the provider
+// cannot connect directly to the database at the given URL.
+func checkSQLClientURL(u string) error {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("failed to parse the database url: %w", err)
+	}
+	if !slices.Contains(sqlClientSchemes, strings.ToLower(pu.Scheme)) {
+		return fmt.Errorf("the %q scheme is not supported, expected one of: %s", pu.Scheme, strings.Join(sqlClientSchemes, ", "))
+	}
+	return nil
+}
+
+// checkSQLClientURLs reports an error on the given attribute for each known
+// database URL the provider cannot connect to directly in order to run it.
+func checkSQLClientURLs(p path.Path, urls ...types.String) (diags diag.Diagnostics) {
+	for _, u := range urls {
+		if u.IsNull() || u.IsUnknown() {
+			continue
+		}
+		if err := checkSQLClientURL(u.ValueString()); err != nil {
+			diags.AddAttributeError(p, "Unsupported database",
+				fmt.Sprintf("%s is run by the provider on the database directly: %s", p, err))
+		}
+	}
+	return diags
+}
+
+// openClient opens a connection to the database at the given URL.
+// The Atlas SQLite driver connects with the "sqlite3" driver name of
+// the CGO-based driver. As releases are built without CGO, SQLite
+// databases are opened with the "sqlite" name of the pure-Go driver.
+func openClient(ctx context.Context, u string) (*sqlclient.Client, error) {
+	pu, err := sqlclient.ParseURL(u)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(pu.Scheme) != SchemaTypeSQLite {
+		return sqlclient.OpenURL(ctx, pu)
+	}
+	dsn := strings.TrimPrefix(pu.String(), pu.Scheme+"://")
+	if pu.Query().Get("mode") == "memory" {
+		// The "file:" prefix is mandatory for memory modes.
+		dsn = "file:" + dsn
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv, err := sqlite.Open(db)
+	if err != nil {
+		if cerr := db.Close(); cerr != nil {
+			err = fmt.Errorf("%w: %v", err, cerr)
+		}
+		return nil, err
+	}
+	return &sqlclient.Client{
+		Name:   "sqlite",
+		DB:     db,
+		URL:    &sqlclient.URL{URL: pu, DSN: dsn, Schema: "main"},
+		Driver: drv,
+	}, nil
+}
+
+// queryValue executes the query on the database at the given URL and returns
+// the first column of the first row. ok is false if the query returned no rows.
+func queryValue(ctx context.Context, u, query string) (v sql.NullString, ok bool, err error) {
+	c, err := openClient(ctx, u)
+	if err != nil {
+		return v, false, fmt.Errorf("failed to open database connection: %w", err)
+	}
+	defer c.Close()
+	rows, err := c.QueryContext(ctx, query)
+	if err != nil {
+		return v, false, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return v, false, rows.Err()
+	}
+	cols, err := rows.Columns()
+	if err != nil {
+		return v, false, err
+	}
+	dest := make([]any, len(cols))
+	for i := range dest {
+		dest[i] = new(any)
+	}
+	dest[0] = &v
+	if err = rows.Scan(dest...); err != nil {
+		return v, false, err
+	}
+	return v, true, rows.Err()
+}
+
+// isFalsy reports if the given query result is considered false.
+func isFalsy(v sql.NullString) bool {
+	if !v.Valid {
+		return true
+	}
+	s := strings.TrimSpace(v.String)
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f == 0
+	}
+	switch strings.ToLower(s) {
+	case "", "f", "false":
+		return true
+	}
+	return false
+}
+
+// execScript executes the statements of the given SQL script on the
+// database at the given URL. All statements run in a single transaction.
+func execScript(ctx context.Context, u, script string) error {
+	stmts, err := migrate.Stmts(script)
+	if err != nil {
+		return fmt.Errorf("failed to parse script: %w", err)
+	}
+	c, err := openClient(ctx, u)
+	if err != nil {
+		return fmt.Errorf("failed to open database connection: %w", err)
+	}
+	defer c.Close()
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt.Text); err != nil {
+			if rerr := tx.Rollback(); rerr != nil {
+				err = fmt.Errorf("%w: %v", err, rerr)
+			}
+			return fmt.Errorf("failed to execute statement %q: %w", stmt.Text, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// dropSchemas drops the given schemas, if they exist, from the database at
+// the given URL. The statements are planned by the driver of the database.
+func dropSchemas(ctx context.Context, u string, names []string) error {
+	c, err := openClient(ctx, u)
+	if err != nil {
+		return fmt.Errorf("failed to open database connection: %w", err)
+	}
+	defer c.Close()
+	changes := make([]schema.Change, 0, len(names))
+	for _, n := range names {
+		changes = append(changes, &schema.DropSchema{
+			S:     schema.New(n),
+			Extra: []schema.Clause{&schema.IfExists{}},
+		})
+	}
+	return c.ApplyChanges(ctx, changes)
+}